@@ -0,0 +1,54 @@
+package main
+
+import (
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// defaultAccent is the accent colour for sites whose theme doesn't set one
+const defaultAccent = lipgloss.Color("#7D56F4")
+
+// accentColor is the colour the browser highlights with: titles, selected
+// items, headings and progress. Everything drawn in it reads it from here.
+var accentColor = defaultAccent
+
+// accentKeys are the theme config settings that can give a site's accent
+// colour, in order of preference. SparkType's themes use the CSS custom
+// property names.
+var accentKeys = []string{"--color-primary", "--color-accent", "accent_color", "primary_color", "accent", "primary"}
+
+// hexColorRegex matches a #rgb or #rrggbb hex colour
+var hexColorRegex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+// siteAccent returns the accent colour set by a site's theme config, or the
+// default if it sets none that is a valid hex colour
+func siteAccent(manifest *sparktype.SiteManifest) lipgloss.Color {
+	if manifest == nil {
+		return defaultAccent
+	}
+	for _, key := range accentKeys {
+		if value, ok := manifest.Theme.Config[key].(string); ok && hexColorRegex.MatchString(value) {
+			return lipgloss.Color(value)
+		}
+	}
+	return defaultAccent
+}
+
+// setAccent makes color the accent colour, restyling the shared styles
+// drawn in it. Styles built per view pick it up when next built.
+func setAccent(color lipgloss.Color) {
+	accentColor = color
+	titleStyle = titleStyle.Background(color)
+	breadcrumbCurrentStyle = breadcrumbCurrentStyle.Foreground(color)
+	yearHeaderStyle = yearHeaderStyle.Foreground(color)
+	helpGroupStyle = helpGroupStyle.Foreground(color)
+}
+
+// applySiteAccent adopts the accent colour of the site being browsed
+func (a *App) applySiteAccent() {
+	setAccent(siteAccent(a.manifest))
+	a.spinner.Style = a.spinner.Style.Foreground(accentColor)
+}
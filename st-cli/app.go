@@ -1,51 +1,196 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// AppState represents the different states of the application
+type AppState int
+
+const (
+	StateMainMenu AppState = iota
+	StateCollectionListing
+	StateContentView
+	StateLoading
+	StateError
+	StateIndexing
+	StateSearch
+	StateTagFilter
+	StateTOC
+	StateBookmarks
+	StateRecent
+	StateLinks
+	StateNotFound
 )
 
 // App represents the main application state
 type App struct {
-	state              AppState
-	siteURL            string
-	client             *Client
-	manifest           *SiteManifest
-	navigationItems    []NavigationItem
-	collectionItems    []CollectionItem
-	collectionTitle    string
-	currentPage        int
-	totalPages         int
-	itemsPerPage       int
-	navigationHistory  [][]NavigationItem // Stack of navigation states for hierarchical navigation
-	selectedIndex      int
-	list               list.Model
-	viewport           viewport.Model
-	content            *ContentFile
-	currentPath        string
-	renderer           *ContentRenderer
-	error              error
-	ready              bool
-	width              int
-	height             int
+	state                AppState
+	siteURL              string
+	client               *sparktype.Client
+	manifest             *sparktype.SiteManifest
+	navigationItems      []NavigationItem
+	collectionItems      []sparktype.CollectionItem // Items shown in the listing, after filtering
+	collectionAll        []sparktype.CollectionItem // Every item in the collection
+	tagFilter            string
+	tagList              list.Model
+	showRaw              bool // Show unrendered markdown in the content view
+	showMetadata         bool // Show the page's frontmatter above it in the content view
+	footnoteBack         int  // Offset to return to from a footnote's note; -1 when none
+	footnoteAt           int  // Offset the view was left at on jumping to a note
+	statusMessage        string
+	statusID             int
+	collectionTitle      string
+	collectionID         string // Collection shown in the listing
+	collectionPath       string // Page the listing belongs to
+	currentPage          int
+	totalPages           int
+	itemsPerPage         int
+	showAllItems         bool // List the whole collection on one page
+	collectionSort       collectionSort
+	groupByYear          bool // Group listings under year headings
+	pageInput            textinput.Model
+	pageInputActive      bool              // The page number prompt has focus
+	navigationHistory    []navigationEntry // Menus and pages visited, for going back and forward
+	navigationCursor     int               // Index of the current menu in navigationHistory
+	treeMode             bool              // Show the whole site as a collapsible tree
+	treeExpanded         map[string]bool   // Paths of the expanded tree nodes
+	selectedIndex        int
+	pendingNumber        string // Digits of an item number being typed
+	pendingNumberID      int    // Identifies the latest digit, so stale timeouts are ignored
+	list                 list.Model
+	listDelegate         list.ItemDelegate // Draws the items of list, for laying them out in columns
+	viewport             viewport.Model
+	contentLines         []string                   // Lines shown in the viewport, for jumping to headings
+	contentWords         int                        // Word count of the page, for the status bar
+	articleSiblings      []sparktype.CollectionItem // Items of the collection the page belongs to, in listing order
+	articleIndex         int                        // Position of the page in articleSiblings, or -1
+	tocList              list.Model
+	linkList             list.Model
+	bookmarks            *bookmarkStore
+	bookmarkList         list.Model
+	bookmarksReturnState AppState // State to return to when the bookmarks close
+	history              *historyStore
+	positions            *positionStore
+	recentList           list.Model
+	showHelp             bool // The help overlay is open
+	paletteOpen          bool // The command palette is open
+	paletteInput         textinput.Model
+	paletteList          list.Model
+	helpViewport         viewport.Model
+	showInfo             bool // The site info overlay is open
+	infoViewport         viewport.Model
+
+	// Searching within the page being viewed
+	findInput         textinput.Model
+	findActive        bool // The find prompt has focus
+	findQuery         string
+	findCaseSensitive bool
+	findMatches       []findMatch
+	findCurrent       int
+
+	content          *sparktype.ContentFile
+	currentPath      string
+	renderer         *sparktype.ContentRenderer
+	wrapToWindow     bool          // Rewrap content to the window width on resize
+	refreshInterval  time.Duration // How often to check the site for changes; 0 disables it
+	showDrafts       bool          // List collection items marked as drafts
+	watcher          *siteWatcher  // Watches a local site for changes
+	error            error
+	missingPath      string    // Page the site doesn't have, shown by StateNotFound
+	versionWarning   string    // Why the site's generator version may not be supported
+	warningDismissed bool      // The version warning has been dismissed
+	cachedAt         time.Time // When the copy on screen was fetched, if the site couldn't be reached for it
+	ready            bool
+	width            int
+	height           int
+	loadID           int                // Identifies the in-flight load; stale results are dropped
+	ctx              context.Context    // Cancelled when the program quits
+	stop             context.CancelFunc // Cancels ctx
+	loadCtx          context.Context    // Cancelled when the in-flight load is abandoned
+	cancelLoad       context.CancelFunc // Cancels loadCtx
+	returnState      AppState           // State to return to if a load is cancelled
+	spinner          spinner.Model
+	loadingLabel     string         // What is being fetched, shown beside the spinner
+	lastLoad         func() tea.Cmd // Repeats the most recent load, for retrying after an error
+
+	// Full-text search, indexed on first use and kept for the session
+	searchQueue    []sparktype.ContentRef
+	searchIndex    []searchEntry
+	searchProgress int
+	indexProgress  progress.Model
+	searchIndexed  bool
+	searchInput    textinput.Model
+	searchList     list.Model
 }
 
 // KeyMap defines the key bindings
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	Refresh  key.Binding
-	NextPage key.Binding
-	PrevPage key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Enter       key.Binding
+	Back        key.Binding
+	Quit        key.Binding
+	Refresh     key.Binding
+	NextPage    key.Binding
+	PrevPage    key.Binding
+	Search      key.Binding
+	Tags        key.Binding
+	Raw         key.Binding
+	Open        key.Binding
+	Export      key.Binding
+	TOC         key.Binding
+	Find        key.Binding
+	FindNext    key.Binding
+	FindPrev    key.Binding
+	FindCase    key.Binding
+	Tree        key.Binding
+	Expand      key.Binding
+	Collapse    key.Binding
+	Bookmark    key.Binding
+	Bookmarks   key.Binding
+	Recent      key.Binding
+	Forward     key.Binding
+	Help        key.Binding
+	ShowAll     key.Binding
+	GoToPage    key.Binding
+	Sort        key.Binding
+	Group       key.Binding
+	JumpLetter  key.Binding
+	NextArticle key.Binding
+	PrevArticle key.Binding
+	Links       key.Binding
+	Metadata    key.Binding
+	Footnote    key.Binding
+	CopyURL     key.Binding
+	Dismiss     key.Binding
+	Top         key.Binding
+	Bottom      key.Binding
+	HalfDown    key.Binding
+	HalfUp      key.Binding
+	PageDown    key.Binding
+	PageUp      key.Binding
+	Palette     key.Binding
+	Info        key.Binding
+	Filter      key.Binding
+	NextColumn  key.Binding
+	PrevColumn  key.Binding
 }
 
 var keys = KeyMap{
@@ -62,8 +207,8 @@ var keys = KeyMap{
 		key.WithHelp("enter/l", "select"),
 	),
 	Back: key.NewBinding(
-		key.WithKeys("esc", "h", "b"),
-		key.WithHelp("esc/h/b", "back"),
+		key.WithKeys("esc", "h"),
+		key.WithHelp("esc/h", "back"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
@@ -81,13 +226,178 @@ var keys = KeyMap{
 		key.WithKeys("left", "p"),
 		key.WithHelp("←/p", "prev page"),
 	),
+	Search: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "search"),
+	),
+	Tags: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "filter by tag"),
+	),
+	Raw: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "toggle raw markdown"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export to markdown"),
+	),
+	TOC: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "contents"),
+	),
+	Find: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "find in page"),
+	),
+	FindNext: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	FindPrev: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	FindCase: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "toggle case sensitivity"),
+	),
+	Tree: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "toggle tree view"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "expand"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("left"),
+		key.WithHelp("←", "collapse"),
+	),
+	Bookmark: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "toggle bookmark"),
+	),
+	Bookmarks: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "bookmarks"),
+	),
+	Recent: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "recently viewed"),
+	),
+	Forward: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "forward"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	ShowAll: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle pages / all items"),
+	),
+	GoToPage: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "go to page"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "change sort order"),
+	),
+	Group: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "group by year"),
+	),
+	JumpLetter: key.NewBinding(
+		key.WithKeys(strings.Split("ABCDEFGHIJKLMNOPQRSTUVWXYZ", "")...),
+		key.WithHelp("A–Z", "jump to letter (sorted by title, where B no longer opens bookmarks)"),
+	),
+	NextArticle: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next item in collection"),
+	),
+	PrevArticle: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "previous item in collection"),
+	),
+	Links: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "links on the page"),
+	),
+	Metadata: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "toggle frontmatter"),
+	),
+	Footnote: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "footnote / back"),
+	),
+	CopyURL: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy URL"),
+	),
+	Dismiss: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "dismiss warning"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("g", "home"),
+		key.WithHelp("g/home", "top of page"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("G", "end"),
+		key.WithHelp("G/end", "bottom of page"),
+	),
+	HalfDown: key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "half page down"),
+	),
+	HalfUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "half page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys(" ", "pgdown"),
+		key.WithHelp("space/pgdn", "page down"),
+	),
+	// b is the bookmark key in every view, so paging back is pgup alone
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "command palette"),
+	),
+	Info: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "site info"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter the list"),
+	),
+	NextColumn: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "next column (wide windows)"),
+	),
+	PrevColumn: key.NewBinding(
+		key.WithKeys("left"),
+		key.WithHelp("←", "previous column (wide windows)"),
+	),
 }
 
 // Styles
 var (
 	titleStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
+			Background(accentColor).
 			Padding(0, 1)
 
 	statusStyle = lipgloss.NewStyle().
@@ -95,11 +405,15 @@ var (
 
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
+
+	draftBadgeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500")).
+			Italic(true)
 )
 
 // NewApp creates a new application instance
-func NewApp(siteURL string) *App {
-	client, err := NewClient(siteURL)
+func NewApp(siteURL string, config Config) *App {
+	client, err := config.NewClient(siteURL)
 	if err != nil {
 		return &App{
 			state:   StateError,
@@ -108,7 +422,7 @@ func NewApp(siteURL string) *App {
 		}
 	}
 
-	renderer, err := NewContentRenderer()
+	renderer, err := sparktype.NewContentRenderer(config.RendererOptions(client)...)
 	if err != nil {
 		return &App{
 			state:   StateError,
@@ -117,85 +431,276 @@ func NewApp(siteURL string) *App {
 		}
 	}
 
+	ctx, stop := context.WithCancel(context.Background())
 	return &App{
-		state:        StateLoading,
-		siteURL:      siteURL,
-		client:       client,
-		renderer:     renderer,
-		itemsPerPage: 10,
+		state:           StateLoading,
+		siteURL:         siteURL,
+		ctx:             ctx,
+		stop:            stop,
+		loadCtx:         ctx,
+		cancelLoad:      func() {},
+		client:          client,
+		renderer:        renderer,
+		bookmarks:       loadBookmarks(filepath.Join(DefaultConfigDir(), "bookmarks.json")),
+		history:         loadHistory(filepath.Join(DefaultConfigDir(), "history.json")),
+		positions:       loadPositions(filepath.Join(DefaultConfigDir(), "positions.json")),
+		wrapToWindow:    config.Wrap < 0,
+		refreshInterval: config.RefreshInterval,
+		showDrafts:      config.Drafts,
+		spinner: spinner.New(
+			spinner.WithSpinner(spinner.Dot),
+			spinner.WithStyle(lipgloss.NewStyle().Foreground(accentColor)),
+		),
+		itemsPerPage: config.PageSize,
 		currentPage:  1,
 	}
 }
 
 // Messages for async operations
 type ManifestLoadedMsg struct {
-	manifest *SiteManifest
+	manifest *sparktype.SiteManifest
 	err      error
+	loadID   int
+	cachedAt time.Time // When the cached copy used in place of the site was fetched, if one was
 }
 
 type ContentLoadedMsg struct {
-	content *ContentFile
-	err     error
+	path     string
+	content  *sparktype.ContentFile
+	err      error
+	loadID   int
+	cachedAt time.Time // When the cached copy used in place of the site was fetched, if one was
 }
 
+// ClearStatusMsg clears a transient status message once it has been shown
+type ClearStatusMsg struct {
+	id int
+}
+
+// statusDuration is how long transient status messages stay on screen
+const statusDuration = 3 * time.Second
+
 // Init initializes the application
 func (a *App) Init() tea.Cmd {
-	return a.loadManifest
+	if a.client == nil {
+		return nil
+	}
+	return tea.Batch(a.loadManifest(), a.scheduleAutoRefresh(), a.startWatching())
 }
 
 // loadManifest fetches the site manifest
-func (a *App) loadManifest() tea.Msg {
-	manifest, err := a.client.FetchManifest()
-	return ManifestLoadedMsg{manifest: manifest, err: err}
+func (a *App) loadManifest() tea.Cmd {
+	loadID, ctx := a.loadID, a.loadCtx
+	a.loadingLabel = tr("Loading site")
+	a.lastLoad = a.loadManifest
+	return tea.Batch(func() tea.Msg {
+		manifest, err := a.client.FetchManifest(ctx)
+		cachedAt, _ := a.client.TakeFallback()
+		return ManifestLoadedMsg{manifest: manifest, err: err, loadID: loadID, cachedAt: cachedAt}
+	}, a.spinner.Tick)
 }
 
 // loadContent fetches content for a given path
 func (a *App) loadContent(path string) tea.Cmd {
-	return func() tea.Msg {
-		content, err := a.client.FetchContent(path)
-		return ContentLoadedMsg{content: content, err: err}
+	return a.loadContentFrom(a.client, path)
+}
+
+// reloadContent fetches content for a given path afresh, checking any copy
+// already fetched with the site
+func (a *App) reloadContent(path string) tea.Cmd {
+	return a.loadContentFrom(a.client.Revalidating(), path)
+}
+
+// loadContentFrom fetches content for a given path with client
+func (a *App) loadContentFrom(client *sparktype.Client, path string) tea.Cmd {
+	loadID, ctx := a.loadID, a.loadCtx
+	a.loadingLabel = fmt.Sprintf(tr("Loading %s"), path)
+	a.lastLoad = func() tea.Cmd { return a.loadContentFrom(client, path) }
+	return tea.Batch(func() tea.Msg {
+		content, err := client.FetchContent(ctx, path)
+		cachedAt, _ := client.TakeFallback()
+		if err == nil {
+			a.renderer.PrefetchImages(ctx, content)
+		}
+		return ContentLoadedMsg{path: path, content: content, err: err, loadID: loadID, cachedAt: cachedAt}
+	}, a.spinner.Tick)
+}
+
+// beginLoading switches to the loading state, remembering the state we came
+// from so the load can be cancelled. Any load still in flight is abandoned,
+// along with its requests.
+func (a *App) beginLoading() {
+	if a.state != StateLoading {
+		a.returnState = a.state
+	}
+	a.state = StateLoading
+	a.loadID++
+	a.cancelLoad()
+	a.loadCtx, a.cancelLoad = context.WithCancel(a.ctx)
+}
+
+// quit cancels every request in flight and ends the program
+func (a *App) quit() (tea.Model, tea.Cmd) {
+	if a.stop != nil {
+		a.stop()
 	}
+	return a, tea.Quit
+}
+
+// cancelLoading abandons the in-flight load and returns to the previous state
+func (a *App) cancelLoading() (tea.Model, tea.Cmd) {
+	if a.manifest == nil {
+		// Nothing to go back to before the manifest has loaded
+		return a.quit()
+	}
+
+	// Bumping the load ID makes the pending result stale when it arrives,
+	// and cancelling its context stops its requests; fetches made by the
+	// view returned to go back to the program's context. The list and
+	// viewport are untouched while loading, so they can be shown again as
+	// they were.
+	a.loadID++
+	a.cancelLoad()
+	a.loadCtx, a.cancelLoad = a.ctx, func() {}
+	a.state = a.returnState
+	if a.state == StateSearch {
+		// Restart the cursor blinking in the search input
+		return a, textinput.Blink
+	}
+	return a, nil
 }
 
 // Update handles messages and updates the application state
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := a.update(msg)
+	a.rememberPosition()
+	return model, cmd
+}
+
+// update does the work of Update
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
 		a.setupUI()
+		if a.state == StateSearch {
+			a.searchList.SetSize(a.width, a.height-4)
+		}
+		if a.showHelp {
+			a.helpViewport.Width = a.width
+			a.helpViewport.Height = a.height - 2
+		}
+		if a.showInfo {
+			a.infoViewport.Width = a.width
+			a.infoViewport.Height = a.height - 2
+		}
+		if a.paletteOpen {
+			a.paletteList.SetSize(a.width, a.height-3)
+		}
+		if a.wrapToWindow && a.renderer != nil {
+			a.renderer.SetWordWrap(a.width)
+		}
+		if a.state == StateContentView {
+			// Reflow the content, keeping roughly the same place in it
+			offset := a.viewport.YOffset
+			a.setupContentView()
+			a.viewport.SetYOffset(offset)
+		}
+		return a, nil
+
+	case SearchIndexedMsg:
+		return a.handleSearchIndexed(msg)
+
+	case AutoRefreshMsg:
+		return a, a.autoRefresh(true)
+
+	case SiteChangedMsg:
+		// Any page may have changed on disk, not just the one being viewed
+		a.client.ForgetContent()
+		return a, tea.Batch(a.autoRefresh(false), a.watcher.wait())
+
+	case AutoRefreshedMsg:
+		return a.handleAutoRefreshed(msg)
+
+	case spinner.TickMsg:
+		// The spinner stops once nothing is loading, by not asking for
+		// another tick
+		if a.state != StateLoading {
+			return a, nil
+		}
+		var cmd tea.Cmd
+		a.spinner, cmd = a.spinner.Update(msg)
+		return a, cmd
+
+	case NumberTimeoutMsg:
+		return a.handleNumberTimeout(msg)
+
+	case ClearStatusMsg:
+		if msg.id == a.statusID {
+			a.statusMessage = ""
+		}
 		return a, nil
 
 	case ManifestLoadedMsg:
+		if msg.loadID != a.loadID {
+			return a, nil
+		}
 		if msg.err != nil {
 			a.state = StateError
 			a.error = msg.err
 			return a, nil
 		}
 		a.manifest = msg.manifest
+		a.cachedAt = msg.cachedAt
+		a.applySiteAccent()
+		a.checkGeneratorVersion()
 		a.buildNavigationItems()
 		a.state = StateMainMenu
 		a.setupUI()
 		return a, nil
 
 	case ContentLoadedMsg:
+		if msg.loadID != a.loadID {
+			return a, nil
+		}
+		if errors.Is(msg.err, sparktype.ErrNotFound) && a.manifest != nil {
+			// A broken link only costs that page, not the session
+			a.state = StateNotFound
+			a.missingPath = msg.path
+			return a, nil
+		}
 		if msg.err != nil {
 			a.state = StateError
 			a.error = msg.err
 			return a, nil
 		}
 		a.content = msg.content
+		a.currentPath = msg.path
+		a.cachedAt = msg.cachedAt
+		a.searchInput.Blur()
+		a.visitPage(NavigationItem{Title: a.content.Title, Type: "page", Path: msg.path})
+		a.findQuery = ""
+		a.findMatches = nil
 
 		// Check if this is a collection listing page
 		if a.content.LayoutConfig != nil && a.content.LayoutConfig.CollectionID != "" {
 			// This page has a collection - show collection listing
 			a.showCollectionListing(a.content.LayoutConfig.CollectionID, a.content.Title)
+			a.collectionPath = msg.path
 			a.state = StateCollectionListing
 			a.setupCollectionListingUI()
 		} else {
 			// Regular content page - show content view
 			a.state = StateContentView
+			if a.renderer != nil {
+				a.contentWords = a.renderer.WordCount(a.content.Content)
+			}
 			a.setupContentView()
+			a.restorePosition()
+			a.locateArticle(msg.path)
+			a.recordVisit()
+			a.savePositions()
 		}
 		return a, nil
 
@@ -209,6 +714,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.list, cmd = a.list.Update(msg)
 	case StateContentView:
 		a.viewport, cmd = a.viewport.Update(msg)
+	case StateSearch:
+		a.searchInput, cmd = a.searchInput.Update(msg)
 	}
 
 	return a, cmd
@@ -216,11 +723,68 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress handles keyboard input
 func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The search box takes every typed key, including ones bound elsewhere
+	if a.state == StateSearch {
+		return a.handleSearchKey(msg)
+	}
+	if a.state == StateContentView && a.findActive {
+		return a.handleFindKey(msg)
+	}
+	if a.state == StateCollectionListing && a.pageInputActive {
+		return a.handlePageJumpKey(msg)
+	}
+	if a.listFilterable() && a.list.SettingFilter() {
+		return a.handleListFilterKey(msg)
+	}
+
+	if a.showHelp {
+		return a.handleHelpKey(msg)
+	}
+	if a.showInfo {
+		return a.handleInfoKey(msg)
+	}
+	if a.paletteOpen {
+		return a.handlePaletteKey(msg)
+	}
+
+	// Any other key abandons an item number being typed
+	if !isDigitKey(msg) {
+		a.pendingNumber = ""
+	}
+
 	switch {
 	case key.Matches(msg, keys.Quit):
-		return a, tea.Quit
+		return a.quit()
+	case key.Matches(msg, keys.Help):
+		return a.showHelpOverlay()
+	case key.Matches(msg, keys.Palette) && a.state != StateLoading && a.state != StateIndexing:
+		return a.showPalette()
+	}
+
+	// While a fetch is in flight, drop navigation and selection keys so an
+	// impatient double press can't trigger a second navigation once it lands
+	if a.state == StateLoading || a.state == StateIndexing {
+		if key.Matches(msg, keys.Back) {
+			return a.cancelLoading()
+		}
+		return a, nil
+	}
+
+	// Sorted by title, every capital letter jumps, so B reaches the
+	// bookmarks from other views only
+	if a.state == StateCollectionListing && a.collectionSort == sortTitle && key.Matches(msg, keys.JumpLetter) {
+		return a.jumpToLetter(msg.String())
+	}
 
+	switch {
 	case key.Matches(msg, keys.Back):
+		if a.state == StateContentView && a.findQuery != "" {
+			a.clearFind()
+			return a, nil
+		}
+		if a.clearListFilter() {
+			return a, nil
+		}
 		return a.handleBack()
 
 	case key.Matches(msg, keys.Enter):
@@ -228,26 +792,110 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, keys.Refresh):
 		return a.handleRefresh()
+
+	case key.Matches(msg, keys.Bookmarks) && a.state != StateBookmarks:
+		return a.showBookmarks()
+
+	case key.Matches(msg, keys.Info) && a.manifest != nil:
+		return a.showInfoOverlay()
+
+	case key.Matches(msg, keys.Dismiss) && a.warningBanner() != "":
+		a.warningDismissed = true
+		return a, nil
+
+	case key.Matches(msg, keys.Filter) && a.listFilterable():
+		return a.startListFilter()
 	}
 
 	// Handle number key navigation and pagination
 	switch a.state {
 	case StateMainMenu:
-		// Check for number key navigation
-		if msg.String() >= "1" && msg.String() <= "9" {
-			num := int(msg.String()[0] - '1') // Convert to 0-based index
-			if num < len(a.navigationItems) {
-				return a.selectNavigationItem(num)
+		if key.Matches(msg, keys.Search) {
+			return a.startSearch()
+		}
+		if key.Matches(msg, keys.Tree) {
+			return a.toggleTreeMode()
+		}
+		if key.Matches(msg, keys.Recent) {
+			return a.showRecent()
+		}
+		if key.Matches(msg, keys.Forward) {
+			return a.goForward()
+		}
+		if a.treeMode && key.Matches(msg, keys.Expand) {
+			return a.expandTreeNode()
+		}
+		if a.treeMode && key.Matches(msg, keys.Collapse) {
+			return a.collapseTreeNode()
+		}
+		if key.Matches(msg, keys.NextColumn, keys.PrevColumn) && a.listColumns() > 1 {
+			delta := 1
+			if key.Matches(msg, keys.PrevColumn) {
+				delta = -1
 			}
+			a.moveColumn(delta)
+			return a, nil
+		}
+		if key.Matches(msg, keys.Bookmark) {
+			// List titles carry their number, so take the title from the menu
+			if wrapper, ok := a.list.SelectedItem().(NavigationItemWrapper); ok && wrapper.Index < len(a.navigationItems) && a.navigationItems[wrapper.Index].Type != "collection" {
+				return a, a.toggleBookmark(a.navigationItems[wrapper.Index].Title, a.navigationItems[wrapper.Index].Path)
+			}
+			return a, nil
+		}
+		// Check for number key navigation
+		if isDigitKey(msg) {
+			return a.handleNumberKey(msg.String())
 		}
 	case StateCollectionListing:
 		// Check for number key navigation
-		if msg.String() >= "1" && msg.String() <= "9" {
-			num := int(msg.String()[0] - '1') // Convert to 0-based index
-			pageItems := a.getCurrentPageItems()
-			if num < len(pageItems) {
-				return a.selectCollectionItem(pageItems[num])
+		if isDigitKey(msg) {
+			return a.handleNumberKey(msg.String())
+		}
+		if key.Matches(msg, keys.Tags) {
+			return a.showTagFilter()
+		}
+		if key.Matches(msg, keys.Sort) {
+			return a.cycleCollectionSort()
+		}
+		if key.Matches(msg, keys.Group) {
+			return a.toggleYearGroups()
+		}
+		if key.Matches(msg, keys.GoToPage) {
+			return a.startPageJump()
+		}
+		if key.Matches(msg, keys.ShowAll) {
+			a.showAllItems = !a.showAllItems
+			a.paginate()
+			a.setupCollectionListingUI()
+			return a, nil
+		}
+		if key.Matches(msg, keys.Bookmark) {
+			// List titles carry their number and any draft badge, so take
+			// the title from the collection
+			if item, ok := a.list.SelectedItem().(CollectionItemWrapper); ok {
+				for _, listed := range a.collectionItems {
+					if listed.Path == item.Path {
+						return a, a.toggleBookmark(listed.Title, listed.Path)
+					}
+				}
+			}
+			return a, nil
+		}
+		if key.Matches(msg, keys.Open) {
+			if item, ok := a.list.SelectedItem().(CollectionItemWrapper); ok {
+				return a, a.openURL(a.urlForPath(item.Path))
 			}
+			return a, nil
+		}
+		// In columns, ←/→ move between them and turn the page from the
+		// outermost ones
+		columns := a.listColumns() > 1
+		if columns && key.Matches(msg, keys.NextColumn) && a.moveColumn(1) {
+			return a, nil
+		}
+		if columns && key.Matches(msg, keys.PrevColumn) && a.moveColumn(-1) {
+			return a, nil
 		}
 		// Handle pagination
 		if key.Matches(msg, keys.NextPage) && a.currentPage < a.totalPages {
@@ -260,13 +908,105 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.setupCollectionListingUI()
 			return a, nil
 		}
+		if columns && key.Matches(msg, keys.NextColumn, keys.PrevColumn) {
+			return a, nil
+		}
+	case StateContentView:
+		if key.Matches(msg, keys.Raw) {
+			// Rebuilding the view also scrolls back to the top
+			a.showRaw = !a.showRaw
+			a.setupContentView()
+			return a, nil
+		}
+		if key.Matches(msg, keys.Metadata) {
+			return a.toggleMetadata()
+		}
+		if key.Matches(msg, keys.Footnote) {
+			return a.jumpFootnote()
+		}
+		if key.Matches(msg, keys.CopyURL) {
+			return a.copyURL()
+		}
+		switch {
+		case key.Matches(msg, keys.Top):
+			a.viewport.GotoTop()
+			return a, nil
+		case key.Matches(msg, keys.Bottom):
+			a.viewport.GotoBottom()
+			return a, nil
+		case key.Matches(msg, keys.HalfDown):
+			a.viewport.HalfViewDown()
+			return a, nil
+		case key.Matches(msg, keys.HalfUp):
+			a.viewport.HalfViewUp()
+			return a, nil
+		case key.Matches(msg, keys.PageDown):
+			a.viewport.ViewDown()
+			return a, nil
+		case key.Matches(msg, keys.PageUp):
+			a.viewport.ViewUp()
+			return a, nil
+		}
+		if key.Matches(msg, keys.Open) {
+			return a, a.openURL(a.urlForPath(a.currentPath))
+		}
+		if key.Matches(msg, keys.Export) {
+			return a, a.exportCurrentPage()
+		}
+		if key.Matches(msg, keys.Bookmark) && a.content != nil {
+			return a, a.toggleBookmark(a.content.Title, a.currentPath)
+		}
+		if key.Matches(msg, keys.NextArticle) {
+			return a.openAdjacentArticle(1)
+		}
+		if key.Matches(msg, keys.PrevArticle) {
+			return a.openAdjacentArticle(-1)
+		}
+		if key.Matches(msg, keys.TOC) {
+			return a.showTOC()
+		}
+		if key.Matches(msg, keys.Links) {
+			return a.showLinks()
+		}
+		if key.Matches(msg, keys.Find) {
+			return a.startFind()
+		}
+		if key.Matches(msg, keys.FindNext) && a.findQuery != "" {
+			a.nextFindMatch(1)
+			return a, nil
+		}
+		if key.Matches(msg, keys.FindPrev) && a.findQuery != "" {
+			a.nextFindMatch(-1)
+			return a, nil
+		}
+	case StateLinks:
+		if isDigitKey(msg) {
+			return a.handleNumberKey(msg.String())
+		}
+	case StateBookmarks:
+		if key.Matches(msg, keys.Bookmark) {
+			return a.removeSelectedBookmark()
+		}
 	}
 
 	// Let the focused component handle other keys (including up/down for list navigation)
 	var cmd tea.Cmd
 	switch a.state {
-	case StateMainMenu, StateCollectionListing:
+	case StateMainMenu:
 		a.list, cmd = a.list.Update(msg)
+	case StateCollectionListing:
+		a.list, cmd = a.list.Update(msg)
+		a.skipYearHeader(key.Matches(msg, keys.Up))
+	case StateTagFilter:
+		a.tagList, cmd = a.tagList.Update(msg)
+	case StateTOC:
+		a.tocList, cmd = a.tocList.Update(msg)
+	case StateLinks:
+		a.linkList, cmd = a.linkList.Update(msg)
+	case StateBookmarks:
+		a.bookmarkList, cmd = a.bookmarkList.Update(msg)
+	case StateRecent:
+		a.recentList, cmd = a.recentList.Update(msg)
 	case StateContentView:
 		a.viewport, cmd = a.viewport.Update(msg)
 	}
@@ -283,8 +1023,30 @@ func (a *App) handleBack() (tea.Model, tea.Cmd) {
 	case StateCollectionListing:
 		a.state = StateMainMenu
 		a.setupUI()
+	case StateTagFilter:
+		a.state = StateCollectionListing
+	case StateTOC, StateLinks:
+		a.state = StateContentView
+	case StateError:
+		// Return to where the failed load started, once there is a site to
+		// show; before that there is nowhere to go back to
+		if a.manifest != nil {
+			a.state = a.returnState
+		}
+	case StateNotFound:
+		a.state = a.returnState
+	case StateBookmarks:
+		a.state = a.bookmarksReturnState
+	case StateRecent:
+		a.state = StateMainMenu
 	case StateMainMenu:
-		return a, tea.Quit
+		if !a.leaveSubmenu() {
+			return a.quit()
+		}
+	}
+	if a.state == StateSearch {
+		// A failed load from the search returns to it
+		return a, textinput.Blink
 	}
 	return a, nil
 }
@@ -294,14 +1056,24 @@ func (a *App) handleEnter() (tea.Model, tea.Cmd) {
 	switch a.state {
 	case StateMainMenu:
 		selectedItem := a.list.SelectedItem()
-		if _, ok := selectedItem.(NavigationItemWrapper); ok {
-			return a.selectNavigationItem(a.list.Index())
+		if wrapper, ok := selectedItem.(NavigationItemWrapper); ok {
+			return a.selectNavigationItem(wrapper.Index)
 		}
 	case StateCollectionListing:
 		selectedItem := a.list.SelectedItem()
 		if item, ok := selectedItem.(CollectionItemWrapper); ok {
 			return a.selectCollectionItem(item.CollectionItem)
 		}
+	case StateTagFilter:
+		return a.selectTagFilter()
+	case StateTOC:
+		return a.selectTOCHeading()
+	case StateLinks:
+		return a.followLink(a.linkList.Index())
+	case StateBookmarks:
+		return a.openBookmark()
+	case StateRecent:
+		return a.openRecent()
 	}
 
 	return a, nil
@@ -314,33 +1086,55 @@ func (a *App) selectNavigationItem(index int) (tea.Model, tea.Cmd) {
 	}
 
 	navItem := a.navigationItems[index]
-	a.currentPath = navItem.Path
-	a.state = StateLoading
+	if navItem.Type == "collection" {
+		return a.openCollection(navItem)
+	}
+	// The tree shows children in place, so only the flat menu descends
+	if len(navItem.Children) > 0 && !a.treeMode {
+		a.enterSubmenu(navItem)
+		return a, nil
+	}
+
+	a.beginLoading()
 	return a, a.loadContent(navItem.Path)
 }
 
 // selectCollectionItem handles collection item selection
-func (a *App) selectCollectionItem(item CollectionItem) (tea.Model, tea.Cmd) {
-	a.currentPath = item.Path
-	a.state = StateLoading
+func (a *App) selectCollectionItem(item sparktype.CollectionItem) (tea.Model, tea.Cmd) {
+	// Next and previous follow the listing the item was picked from
+	a.articleSiblings = a.collectionItems
+	a.beginLoading()
 	return a, a.loadContent(item.Path)
 }
 
 // handleRefresh refreshes the current view
 func (a *App) handleRefresh() (tea.Model, tea.Cmd) {
 	switch a.state {
+	case StateError, StateNotFound:
+		return a.retryLastLoad()
 	case StateMainMenu, StateCollectionListing:
-		a.state = StateLoading
-		return a, a.loadManifest
+		a.beginLoading()
+		return a, a.loadManifest()
 	case StateContentView:
 		if a.currentPath != "" {
-			a.state = StateLoading
-			return a, a.loadContent(a.currentPath)
+			a.beginLoading()
+			return a, a.reloadContent(a.currentPath)
 		}
 	}
 	return a, nil
 }
 
+// retryLastLoad repeats the load that failed. The state it started from is
+// kept, so that cancelling or going back still returns there.
+func (a *App) retryLastLoad() (tea.Model, tea.Cmd) {
+	if a.lastLoad == nil {
+		return a, nil
+	}
+	a.state = StateLoading
+	a.loadID++
+	return a, a.lastLoad()
+}
+
 // setupUI initializes the UI components
 func (a *App) setupUI() {
 	if a.width == 0 || a.height == 0 {
@@ -350,19 +1144,36 @@ func (a *App) setupUI() {
 	// Setup list component with numbered items
 	items := make([]list.Item, len(a.navigationItems))
 	for i, navItem := range a.navigationItems {
-		// Add number prefix to title
-		numberedTitle := fmt.Sprintf("%d. %s", i+1, navItem.Title)
+		// Add number prefix to title, indented by depth, marking pages
+		// that open a submenu or can be expanded in the tree
+		marker := ""
+		if a.treeMode {
+			switch {
+			case a.treeExpanded[navItem.Path]:
+				marker = "▾ "
+			case expandable(navItem):
+				marker = "▸ "
+			default:
+				marker = "  "
+			}
+		}
+		numberedTitle := fmt.Sprintf("%s%s%d. %s", strings.Repeat("  ", navItem.Level), marker, i+1, a.renderer.Emojify(navItem.Title))
+		if !a.treeMode && len(navItem.Children) > 0 {
+			numberedTitle += " ›"
+		}
 		navItemCopy := navItem
 		navItemCopy.Title = numberedTitle
-		items[i] = NavigationItemWrapper{NavigationItem: navItemCopy}
+		items[i] = NavigationItemWrapper{NavigationItem: navItemCopy, Index: i}
 	}
 
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(accentColor).
 		Bold(true)
+	delegate.Styles.FilterMatch = filterMatchStyle
 
 	a.list = list.New(items, delegate, a.width, a.height-4)
+	a.listDelegate = delegate
 	a.list.Title = a.getTitle()
 	a.list.SetShowStatusBar(false)
 	a.list.SetShowHelp(false)
@@ -378,7 +1189,13 @@ func (a *App) setupContentView() {
 
 	// Render markdown content using glamour
 	var content string
-	if a.renderer != nil {
+	if a.showRaw {
+		raw, err := markdownDocument(a.content)
+		if err != nil {
+			raw = a.content.Content
+		}
+		content = raw
+	} else if a.renderer != nil {
 		rendered, err := a.renderer.RenderContent(a.content)
 		if err != nil {
 			// Fallback to simple formatting
@@ -389,9 +1206,79 @@ func (a *App) setupContentView() {
 	} else {
 		content = fmt.Sprintf("# %s\n\n%s", a.content.Title, a.content.Content)
 	}
+	if a.showMetadata {
+		content = renderMetadata(a.content.Metadata, a.width) + "\n" + content
+	}
 
-	a.viewport = viewport.New(a.width, a.height-4)
+	// Leave room for the title, status bar and help line
+	a.viewport = viewport.New(a.width, a.height-5)
 	a.viewport.SetContent(content)
+	a.contentLines = strings.Split(content, "\n")
+	a.footnoteBack = -1
+	if a.findQuery != "" {
+		a.runFind()
+	}
+}
+
+// contentStatusBar describes the page being viewed and how far through it
+// the view is scrolled
+func (a *App) contentStatusBar() string {
+	parts := []string{a.currentPath}
+	if a.contentWords == 1 {
+		parts = append(parts, tr("1 word"))
+	} else {
+		parts = append(parts, fmt.Sprintf(tr("%d words"), a.contentWords))
+	}
+	parts = append(parts, fmt.Sprintf("%d%%", int(a.viewport.ScrollPercent()*100)))
+	return statusStyle.MaxWidth(a.width).Render(strings.Join(parts, " · "))
+}
+
+// setStatus shows a transient message in place of the help line
+func (a *App) setStatus(message string) tea.Cmd {
+	a.statusID++
+	a.statusMessage = message
+	id := a.statusID
+	return tea.Tick(statusDuration, func(time.Time) tea.Msg {
+		return ClearStatusMsg{id: id}
+	})
+}
+
+// helpLine returns the help text, or the status message while one is showing
+func (a *App) helpLine(help string) string {
+	if a.pendingNumber != "" {
+		return statusStyle.Render(a.pendingNumberStatus())
+	}
+	if a.statusMessage != "" {
+		return statusStyle.Render(a.statusMessage)
+	}
+	return helpStyle.Render(help)
+}
+
+// exportCurrentPage saves the content being viewed to the working directory
+func (a *App) exportCurrentPage() tea.Cmd {
+	if a.content == nil {
+		return nil
+	}
+
+	path, err := exportContent(a.content, ".", a.slugForPath(a.currentPath))
+	if err != nil {
+		return a.setStatus(fmt.Sprintf(tr("Export failed: %v"), err))
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return a.setStatus(fmt.Sprintf(tr("Saved to %s"), path))
+}
+
+// openURL opens a URL in the system browser, reporting the outcome in the status line
+func (a *App) openURL(url string) tea.Cmd {
+	if url == "" {
+		return a.setStatus(tr("No URL available for this page"))
+	}
+	if err := openBrowser(url); err != nil {
+		return a.setStatus(fmt.Sprintf(tr("Could not open browser: %v"), err))
+	}
+	return a.setStatus(fmt.Sprintf(tr("Opened %s"), url))
 }
 
 // getTitle returns the appropriate title for the current state
@@ -404,14 +1291,14 @@ func (a *App) getTitle() string {
 	case StateMainMenu:
 		return a.renderSiteTitle()
 	case StateCollectionListing:
-		return a.collectionTitle
+		return a.renderer.Emojify(a.collectionTitle)
 	case StateContentView:
 		if a.content != nil {
-			return fmt.Sprintf("%s - %s", a.manifest.Title, a.content.Title)
+			return a.renderer.Emojify(fmt.Sprintf("%s - %s", a.manifest.Title, a.content.Title))
 		}
 	}
 
-	return a.manifest.Title
+	return a.renderer.Emojify(a.manifest.Title)
 }
 
 // renderSiteTitle renders the site title with ASCII art styling
@@ -420,15 +1307,15 @@ func (a *App) renderSiteTitle() string {
 		return "SparkType CLI"
 	}
 
-	title := a.manifest.Title
+	title := a.renderer.Emojify(a.manifest.Title)
 	// Simple ASCII art-style border
-	border := strings.Repeat("═", len(title)+4)
+	border := strings.Repeat("═", lipgloss.Width(title)+4)
 
 	titleBlock := fmt.Sprintf("╔%s╗\n║  %s  ║\n╚%s╝", border, title, border)
 
 	// Add site description if available
 	if a.manifest.Description != "" {
-		titleBlock += fmt.Sprintf("\n\n%s", a.manifest.Description)
+		titleBlock += fmt.Sprintf("\n\n%s", a.renderer.Emojify(a.manifest.Description))
 	}
 
 	return titleBlock
@@ -441,24 +1328,36 @@ func (a *App) showCollectionListing(collectionID, title string) {
 	}
 
 	// Get items for this collection
-	var items []CollectionItem
+	var items []sparktype.CollectionItem
 	for _, item := range a.manifest.CollectionItems {
 		if item.CollectionID == collectionID {
 			items = append(items, item)
 		}
 	}
 
-	// Sort by date (most recent first)
-	a.sortCollectionItemsByDate(items)
+	a.sortCollectionItems(items)
 
-	a.collectionItems = items
+	a.collectionAll = a.withoutDrafts(items)
+	a.collectionID = collectionID
 	a.collectionTitle = title
+	a.tagFilter = ""
+	a.applyCollectionFilter()
+}
+
+// paginate splits the listed items into pages and returns to the first one
+func (a *App) paginate() {
 	a.currentPage = 1
-	a.totalPages = (len(items) + a.itemsPerPage - 1) / a.itemsPerPage
+	a.totalPages = (len(a.collectionItems) + a.itemsPerPage - 1) / a.itemsPerPage
+	if a.showAllItems && a.totalPages > 1 {
+		a.totalPages = 1
+	}
 }
 
 // getCurrentPageItems returns the items for the current page
-func (a *App) getCurrentPageItems() []CollectionItem {
+func (a *App) getCurrentPageItems() []sparktype.CollectionItem {
+	if a.showAllItems {
+		return a.collectionItems
+	}
 	start := (a.currentPage - 1) * a.itemsPerPage
 	end := start + a.itemsPerPage
 	if end > len(a.collectionItems) {
@@ -477,96 +1376,248 @@ func (a *App) setupCollectionListingUI() {
 	items := make([]list.Item, len(pageItems))
 
 	// Fetch metadata for all items on this page
-	a.fetchCollectionItemsMetadata(pageItems, func(itemsWithMetadata []CollectionItemWrapper) {
+	a.fetchCollectionItemsMetadata(a.loadCtx, pageItems, func(itemsWithMetadata []CollectionItemWrapper) {
 		for i, itemWithMetadata := range itemsWithMetadata {
 			items[i] = itemWithMetadata
 		}
 
 		delegate := list.NewDefaultDelegate()
 		delegate.Styles.SelectedTitle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7D56F4")).
+			Foreground(accentColor).
 			Bold(true)
+		delegate.Styles.FilterMatch = filterMatchStyle
+
+		var itemDelegate list.ItemDelegate = delegate
+		if a.groupByYear {
+			items = withYearHeaders(itemsWithMetadata)
+			itemDelegate = yearGroupDelegate{delegate}
+		}
 
-		a.list = list.New(items, delegate, a.width, a.height-4)
+		a.list = list.New(items, itemDelegate, a.width, a.height-4)
+		a.listDelegate = itemDelegate
 		a.list.Title = a.getTitle()
 		a.list.SetShowStatusBar(false)
 		a.list.SetShowHelp(false)
+		a.skipYearHeader(false)
 
 		a.ready = true
 	})
 }
 
-// fetchCollectionItemsMetadata fetches date and description for collection items
-func (a *App) fetchCollectionItemsMetadata(items []CollectionItem, callback func([]CollectionItemWrapper)) {
+// fetchCollectionItemsMetadata fetches date and description for collection
+// items, giving up on those not fetched when ctx is cancelled
+func (a *App) fetchCollectionItemsMetadata(ctx context.Context, items []sparktype.CollectionItem, callback func([]CollectionItemWrapper)) {
 	itemsWithMetadata := make([]CollectionItemWrapper, len(items))
 
 	// For now, we'll fetch synchronously for simplicity
 	// In a real implementation, this could be done asynchronously
 	for i, item := range items {
 		// Add number prefix to title
-		numberedTitle := fmt.Sprintf("%d. %s", i+1, item.Title)
+		numberedTitle := fmt.Sprintf("%d. %s", i+1, a.renderer.Emojify(item.Title))
 
 		// Fetch content to get date and description
-		content, err := a.client.FetchContent(item.Path)
+		content, err := a.client.FetchContent(ctx, item.Path)
 
 		var dateStr, description string
+		hasBanner := false
 		if err == nil {
 			if !content.Date.IsZero() {
-				dateStr = content.Date.Format("2 January 2006")
+				dateStr = formatDate(content.Date, "2 January 2006")
+			}
+			description = a.renderer.Emojify(content.Description)
+			if content.Draft {
+				numberedTitle += " " + draftBadgeStyle.Render(tr("[draft]"))
+			}
+			for _, image := range sparktype.ExtractImageInfo(content.Metadata) {
+				hasBanner = hasBanner || image.URL != ""
 			}
-			description = content.Description
 		} else {
 			// Fallback if content can't be fetched
-			dateStr = "Date unavailable"
+			dateStr = tr("Date unavailable")
 			description = ""
 		}
 
 		itemsWithMetadata[i] = CollectionItemWrapper{
-			CollectionItem: CollectionItem{
+			CollectionItem: sparktype.CollectionItem{
 				CollectionID: item.CollectionID,
 				Slug:         item.Slug,
 				Path:         item.Path,
 				Title:        numberedTitle,
 				URL:          item.URL,
+				Date:         item.Date,
+				Tags:         item.Tags,
+				Draft:        item.Draft,
 			},
 			ItemDate:        dateStr,
 			ItemDescription: description,
+			HasBanner:       hasBanner,
 		}
 	}
 
 	callback(itemsWithMetadata)
 }
 
+// errorHelp lists the ways out of the error screen
+func (a *App) errorHelp() string {
+	var actions []string
+	if a.lastLoad != nil {
+		actions = append(actions, tr("r: retry"))
+	}
+	if a.manifest != nil {
+		actions = append(actions, tr("esc: back"))
+	}
+	return strings.Join(append(actions, tr("q: quit")), " • ")
+}
+
+// errorView describes the error that stopped a load. A site that isn't a
+// SparkType site gets its own explanation, since retrying won't help.
+func (a *App) errorView() string {
+	var manifestErr *sparktype.ManifestError
+	if errors.As(a.error, &manifestErr) {
+		var builder strings.Builder
+		builder.WriteString(titleStyle.Render(tr("Not a SparkType site")))
+		builder.WriteString(fmt.Sprintf("\n\n"+tr("%s has a manifest, but:")+"\n", a.client.GetBaseURL()))
+		for _, problem := range manifestErr.Problems {
+			builder.WriteString("  • " + problem + "\n")
+		}
+		builder.WriteString("\n" + tr("Check that the URL points at the root of a SparkType site.") + "\n\n")
+		builder.WriteString(helpStyle.Render(a.errorHelp()))
+		return builder.String()
+	}
+	if hint := errorHint(a.error); hint != "" {
+		return fmt.Sprintf(tr("Error: %v")+"\n\n%s\n\n%s", a.error, tr(hint), helpStyle.Render(a.errorHelp()))
+	}
+	return fmt.Sprintf(tr("Error: %v")+"\n\n%s", a.error, helpStyle.Render(a.errorHelp()))
+}
+
+// errorHint suggests what to do about a failed load, by the kind of error
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, sparktype.ErrUnauthorized):
+		return "The site needs credentials; give them with --user or --token."
+	case errors.Is(err, sparktype.ErrNotFound):
+		return "Check the URL, or give the manifest's location with --manifest-path."
+	case errors.Is(err, sparktype.ErrNetwork):
+		return "The site couldn't be reached; check your connection and the URL."
+	case errors.Is(err, sparktype.ErrParse):
+		return "The site sent a file that couldn't be read."
+	}
+	return ""
+}
+
+// notFoundView explains that a page the site links to is missing
+func (a *App) notFoundView() string {
+	var builder strings.Builder
+	builder.WriteString(titleStyle.Render(tr("Page not found")))
+	builder.WriteString(fmt.Sprintf("\n\n"+tr("%s isn't on the site. It may have been moved or removed.")+"\n\n", a.missingPath))
+	builder.WriteString(helpStyle.Render(a.errorHelp()))
+	return builder.String()
+}
+
+// loadingView shows the spinner and what is being fetched
+func (a *App) loadingView() string {
+	return fmt.Sprintf("%s%s...\n\n%s", a.spinner.View(), a.loadingLabel, helpStyle.Render(tr("esc: cancel")))
+}
+
 // View renders the application
 func (a *App) View() string {
 	if !a.ready && a.state != StateError {
-		return "Loading..."
+		return a.loadingView()
+	}
+	if a.showHelp {
+		return a.helpView()
+	}
+	if a.showInfo {
+		return a.infoView()
+	}
+	if a.paletteOpen {
+		return a.paletteView()
 	}
 
 	switch a.state {
 	case StateError:
-		return fmt.Sprintf("Error: %v\n\nPress 'q' to quit.", a.error)
+		return a.errorView()
+
+	case StateNotFound:
+		return a.notFoundView()
 
 	case StateLoading:
-		return "Loading..."
+		return a.loadingView()
+
+	case StateIndexing:
+		return a.indexingView()
+
+	case StateSearch:
+		return a.searchView()
 
 	case StateMainMenu:
-		help := helpStyle.Render("↑/↓: navigate • 1-9: select by number • enter: select • q: quit • r: refresh")
-		return fmt.Sprintf("%s\n%s", a.list.View(), help)
+		help := a.helpLine(tr("↑/↓: navigate • 0-9: select by number • enter: select • /: filter • s: search • v: tree view • b/B: bookmark/bookmarks • R: recent • ?: all keys • q: quit • r: refresh"))
+		if a.treeMode {
+			help = a.helpLine(tr("↑/↓: navigate • →/←: expand/collapse • enter: open • v: flat menu • /: filter • s: search • q: quit"))
+		} else if a.inSubmenu() {
+			help = a.helpLine(tr("↑/↓: navigate • 0-9: select by number • enter: select • /: filter • esc: up a level • f: forward • q: quit"))
+		} else if a.canGoForward() {
+			help = fmt.Sprintf("%s | %s", help, tr("f: forward"))
+		}
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s", a.listView(), help))
 
 	case StateCollectionListing:
-		help := helpStyle.Render("↑/↓: navigate • 1-9: select by number • ←/→: prev/next page • esc: back • q: quit")
+		paging := tr("a: show all")
+		if a.showAllItems {
+			paging = tr("a: show pages")
+		} else if a.totalPages > 1 {
+			paging = tr("←/→: prev/next page • g: go to page • a: show all")
+		}
+		help := a.helpLine(fmt.Sprintf(tr("↑/↓: navigate • 0-9: select by number • /: filter • %s • s: sort (%s) • y: group by year • t: filter by tag • o: open in browser • b: bookmark • esc: back • q: quit"), paging, tr(a.collectionSort.String())))
+		if a.tagFilter != "" {
+			help = fmt.Sprintf("%s | %s", help, fmt.Sprintf(tr("Tag: #%s (%d of %d)"), a.tagFilter, len(a.collectionItems), len(a.collectionAll)))
+		}
+		if a.collectionSort == sortTitle {
+			help = fmt.Sprintf("%s | %s", help, tr("A–Z: jump to letter"))
+		}
 		if a.totalPages > 1 {
-			pageInfo := fmt.Sprintf("Page %d of %d", a.currentPage, a.totalPages)
+			pageInfo := fmt.Sprintf(tr("Page %d of %d"), a.currentPage, a.totalPages)
 			help = fmt.Sprintf("%s | %s", help, pageInfo)
 		}
-		return fmt.Sprintf("%s\n%s", a.list.View(), help)
+		if a.pageInputActive {
+			help = a.pageJumpPrompt()
+		}
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s", a.listView(), help))
+
+	case StateTagFilter:
+		help := helpStyle.Render(tr("↑/↓: navigate • enter: apply filter • esc: cancel"))
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s", a.tagList.View(), help))
+
+	case StateTOC:
+		help := helpStyle.Render(tr("↑/↓: navigate • enter: jump to heading • esc: back to page"))
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s", a.tocList.View(), help))
+
+	case StateLinks:
+		help := a.helpLine(tr("↑/↓: navigate • 0-9: follow by number • enter: follow link • esc: back to page"))
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s", a.linkList.View(), help))
+
+	case StateBookmarks:
+		help := a.helpLine(tr("↑/↓: navigate • enter: open • b: remove bookmark • esc: back"))
+		return fmt.Sprintf("%s\n%s", a.bookmarkList.View(), help)
+
+	case StateRecent:
+		help := a.helpLine(tr("↑/↓: navigate • enter: open • esc: back"))
+		return fmt.Sprintf("%s\n%s", a.recentList.View(), help)
 
 	case StateContentView:
-		help := helpStyle.Render("↑/↓: scroll • esc: back • q: quit")
+		mode := tr("rendered")
+		if a.showRaw {
+			mode = tr("raw")
+		}
+		help := a.helpLine(fmt.Sprintf(tr("↑/↓: scroll%s • /: find • c: contents • L: links • t: toggle raw (%s) • m: frontmatter • o: open in browser • e: export • b: bookmark • esc: back • q: quit"), a.articleHelp(), mode))
+		if a.findActive {
+			help = a.findPrompt()
+		} else if status := a.findStatus(); status != "" && a.statusMessage == "" {
+			help = statusStyle.Render(status)
+		}
 		title := titleStyle.Render(a.getTitle())
-		return fmt.Sprintf("%s\n%s\n%s", title, a.viewport.View(), help)
+		return a.withBreadcrumbs(fmt.Sprintf("%s\n%s\n%s\n%s", title, a.viewport.View(), a.contentStatusBar(), help))
 	}
 
 	return "Unknown state"
-}
\ No newline at end of file
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"st-cli/pkg/sparktype"
+)
+
+// testManifest is a minimal site with two pages
+const testManifest = `{"siteId": "test", "title": "Test site", "structure": [
+	{"type": "page", "title": "About", "path": "content/about.md"},
+	{"type": "page", "title": "Contact", "path": "content/contact.md"}
+]}`
+
+// newTestApp returns an app showing the main menu of a stub site, with its
+// config kept out of the user's own
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_site/manifest.json":
+			w.Write([]byte(testManifest))
+		case "/_site/content/about.md", "/_site/content/contact.md":
+			w.Write([]byte("# Page\n\nSome text.\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	a := NewApp(srv.URL, DefaultConfig())
+	if a.state == StateError {
+		t.Fatalf("NewApp() error = %v", a.error)
+	}
+	t.Cleanup(func() { a.quit() })
+	a.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	a.Update(runLoad(a.loadManifest()))
+	if a.state != StateMainMenu {
+		t.Fatalf("state = %v after loading the manifest, want the main menu", a.state)
+	}
+	return a
+}
+
+// runLoad runs a load command, returning the result of its fetch rather than
+// the spinner tick batched with it
+func runLoad(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		return batch[0]()
+	}
+	return msg
+}
+
+// keyPress returns the message for a key, named as in the key bindings
+func keyPress(name string) tea.KeyMsg {
+	switch name {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+func TestKeysIgnoredWhileLoading(t *testing.T) {
+	a := newTestApp(t)
+
+	_, load := a.Update(keyPress("enter"))
+	if a.state != StateLoading || load == nil {
+		t.Fatalf("enter on a page: state = %v, cmd = %v, want a load", a.state, load)
+	}
+	loadID, ctx := a.loadID, a.loadCtx
+
+	for _, name := range []string{"enter", "down", "r", "2", "enter"} {
+		_, cmd := a.Update(keyPress(name))
+		if cmd != nil {
+			t.Errorf("%s while loading returned a command", name)
+		}
+		if a.loadID != loadID {
+			t.Errorf("%s while loading changed the load ID from %d to %d", name, loadID, a.loadID)
+		}
+		if a.state != StateLoading {
+			t.Errorf("%s while loading left state %v", name, a.state)
+		}
+	}
+	if ctx.Err() != nil {
+		t.Error("keys while loading cancelled the load")
+	}
+
+	msg := runLoad(load)
+	a.Update(msg)
+	if a.state != StateContentView {
+		t.Errorf("state = %v once the load finished, want the content view", a.state)
+	}
+}
+
+func TestEscCancelsLoad(t *testing.T) {
+	a := newTestApp(t)
+
+	_, load := a.Update(keyPress("enter"))
+	if a.state != StateLoading || load == nil {
+		t.Fatalf("enter on a page: state = %v, cmd = %v, want a load", a.state, load)
+	}
+	returnState, ctx := a.returnState, a.loadCtx
+	if returnState != StateMainMenu {
+		t.Errorf("returnState = %v, want the main menu", returnState)
+	}
+
+	a.Update(keyPress("esc"))
+	if a.state != returnState {
+		t.Errorf("state = %v after esc, want %v", a.state, returnState)
+	}
+	if ctx.Err() == nil {
+		t.Error("esc did not cancel the load's context")
+	}
+
+	// The cancelled load's result is dropped when it arrives
+	a.Update(runLoad(load))
+	if a.state != returnState {
+		t.Errorf("state = %v after the cancelled load returned, want %v", a.state, returnState)
+	}
+}
+
+func TestJumpToLetter(t *testing.T) {
+	a := newTestApp(t)
+	a.state = StateCollectionListing
+	a.collectionSort = sortTitle
+	a.itemsPerPage = 2
+	for _, title := range []string{"Damson", "apple", "Elder", "Cherry", "Banana"} {
+		slug := strings.ToLower(title)
+		a.collectionAll = append(a.collectionAll, sparktype.CollectionItem{Slug: slug, Title: title, Path: "content/fruit/" + slug + ".md"})
+	}
+	a.sortCollectionItems(a.collectionAll)
+	a.applyCollectionFilter()
+	a.setupCollectionListingUI()
+
+	selected := func() string {
+		item, _ := a.list.SelectedItem().(CollectionItemWrapper)
+		return item.Slug
+	}
+
+	tests := []struct {
+		key      string
+		wantPage int
+		want     string
+	}{
+		{"D", 2, "damson"},
+		{"A", 1, "apple"},
+		{"E", 3, "elder"},
+		{"C", 2, "cherry"},
+		{"B", 1, "banana"},
+		{"Z", 1, "banana"},
+	}
+	for _, tt := range tests {
+		a.Update(keyPress(tt.key))
+		if a.currentPage != tt.wantPage || selected() != tt.want {
+			t.Errorf("%s: page %d, selected %q; want page %d, %q", tt.key, a.currentPage, selected(), tt.wantPage, tt.want)
+		}
+	}
+	if a.statusMessage == "" {
+		t.Error("no status for a letter no title starts with")
+	}
+
+	if a.state != StateCollectionListing {
+		t.Errorf("state = %v after the letters, want the listing", a.state)
+	}
+
+	// Other orders leave the letters alone, and B goes to the bookmarks,
+	// which are empty here
+	a.collectionSort = sortNewest
+	a.Update(keyPress("A"))
+	if a.currentPage != 1 || selected() != "banana" {
+		t.Errorf("A sorted newest first turned to page %d, selected %q", a.currentPage, selected())
+	}
+	a.Update(keyPress("B"))
+	if want := tr("No bookmarks yet; press b on a page to add one"); a.statusMessage != want {
+		t.Errorf("B sorted newest first: status %q, want %q", a.statusMessage, want)
+	}
+}
+
+func TestForwardReopensPage(t *testing.T) {
+	a := newTestApp(t)
+
+	open := func(keys ...string) {
+		t.Helper()
+		var load tea.Cmd
+		for _, name := range keys {
+			_, load = a.Update(keyPress(name))
+		}
+		if a.state != StateLoading {
+			t.Fatalf("%v: state = %v, want a load", keys, a.state)
+		}
+		a.Update(runLoad(load))
+		if a.state != StateContentView {
+			t.Fatalf("%v: state = %v once loaded, want the content view", keys, a.state)
+		}
+	}
+
+	open("enter")
+	a.Update(keyPress("esc"))
+	if a.state != StateMainMenu {
+		t.Fatalf("state = %v after esc, want the main menu", a.state)
+	}
+	open("f")
+	if a.currentPath != "content/about.md" {
+		t.Errorf("forward opened %q, want the page left with esc", a.currentPath)
+	}
+
+	// Opening another page replaces the one forward returns to
+	a.Update(keyPress("esc"))
+	open("down", "enter")
+	a.Update(keyPress("esc"))
+	open("f")
+	if a.currentPath != "content/contact.md" {
+		t.Errorf("forward opened %q, want the page most recently left", a.currentPath)
+	}
+}
+
+func TestAnchorLinks(t *testing.T) {
+	a := newTestApp(t)
+	_, load := a.Update(keyPress("enter"))
+	a.Update(runLoad(load))
+	a.content.Content = "# Top\n\nSee [setup](#setup), [setup again](#setup-1) and [nowhere](#missing).\n\n" +
+		strings.Repeat("Filler.\n\n", 40) + "## Setup\n\nFirst.\n\n" +
+		strings.Repeat("More.\n\n", 40) + "## Setup\n\nSecond.\n\n" + strings.Repeat("Tail.\n\n", 40)
+	a.setupContentView()
+
+	a.Update(keyPress("L"))
+	if a.state != StateLinks {
+		t.Fatalf("state = %v after L, want the links view", a.state)
+	}
+	items := a.linkList.Items()
+	if len(items) != 3 {
+		t.Fatalf("links view lists %d links, want 3", len(items))
+	}
+	first, second := items[0].(LinkItem), items[1].(LinkItem)
+	if first.Heading != "Setup" || first.Line < 0 || second.Line <= first.Line {
+		t.Errorf("anchors resolved to %q at line %d and %q at line %d, want both Setup headings in order", first.Heading, first.Line, second.Heading, second.Line)
+	}
+
+	a.Update(keyPress("2"))
+	if a.state != StateContentView || a.viewport.YOffset != second.Line {
+		t.Errorf("following the second anchor: state = %v, offset %d, want the content view at line %d", a.state, a.viewport.YOffset, second.Line)
+	}
+
+	a.Update(keyPress("L"))
+	a.Update(keyPress("3"))
+	if a.state != StateContentView || a.statusMessage == "" {
+		t.Errorf("following a missing anchor: state = %v, status %q, want the content view with a status", a.state, a.statusMessage)
+	}
+}
+
+func TestCancelledLoadReturnsToSearch(t *testing.T) {
+	a := newTestApp(t)
+	a.searchIndex = []searchEntry{{Title: "About", Path: "content/about.md", Body: "Some text."}}
+	a.searchIndexed = true
+	a.Update(keyPress("s"))
+	for _, r := range "text" {
+		a.Update(keyPress(string(r)))
+	}
+
+	_, load := a.Update(keyPress("enter"))
+	if a.state != StateLoading || load == nil {
+		t.Fatalf("enter on a result: state = %v, cmd = %v, want a load", a.state, load)
+	}
+	a.Update(keyPress("esc"))
+	if a.state != StateSearch || !a.searchInput.Focused() {
+		t.Fatalf("esc while loading: state = %v, input focused %v; want the search, focused", a.state, a.searchInput.Focused())
+	}
+	a.Update(keyPress("s"))
+	if got := a.searchInput.Value(); got != "texts" {
+		t.Errorf("typing after the cancelled load left the query %q, want %q", got, "texts")
+	}
+}
+
+func TestBookmarkFromListing(t *testing.T) {
+	a := newTestApp(t)
+	a.state = StateCollectionListing
+	a.collectionAll = []sparktype.CollectionItem{
+		{Slug: "apple", Title: "Apple", Path: "content/fruit/apple.md"},
+		{Slug: "banana", Title: "Banana", Path: "content/fruit/banana.md"},
+	}
+	a.applyCollectionFilter()
+	a.setupCollectionListingUI()
+
+	a.Update(keyPress("down"))
+	a.Update(keyPress("b"))
+	bookmarks := a.bookmarks.list(a.client.GetBaseURL())
+	if len(bookmarks) != 1 {
+		t.Fatalf("%d bookmarks after b, want 1", len(bookmarks))
+	}
+	if got := bookmarks[0]; got.Title != "Banana" || got.Path != "content/fruit/banana.md" {
+		t.Errorf("bookmarked %q at %q, want the item's own title", got.Title, got.Path)
+	}
+}
+
+func TestFrenchKeyHelp(t *testing.T) {
+	setLocale("fr")
+	t.Cleanup(func() { setLocale(defaultLocale) })
+
+	for _, group := range keys.helpGroups() {
+		if tr(group.Title) == group.Title {
+			t.Errorf("help group %q is not translated", group.Title)
+		}
+		for _, binding := range group.Bindings {
+			if desc := binding.Help().Desc; tr(desc) == desc {
+				t.Errorf("help for %s, %q, is not translated", binding.Help().Key, desc)
+			}
+		}
+	}
+	for _, command := range paletteCommands() {
+		if tr(command.Name) == command.Name {
+			t.Errorf("command %q is not translated", command.Name)
+		}
+	}
+	for _, title := range []string{"Keys", "Site info", "Bookmarks", "Recently viewed", "Links", "Contents", "Go to page: ", "key: ", "[draft]"} {
+		if tr(title) == title {
+			t.Errorf("title %q is not translated", title)
+		}
+	}
+}
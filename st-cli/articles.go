@@ -0,0 +1,74 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// locateArticle finds the page being viewed among the items of its
+// collection, so that the next and previous items can be opened from it.
+// Items opened from a listing keep that listing's order and filter; others
+// get their whole collection in the current sort order.
+func (a *App) locateArticle(path string) {
+	a.articleIndex = -1
+	for i, item := range a.articleSiblings {
+		if item.Path == path {
+			a.articleIndex = i
+			break
+		}
+	}
+
+	if a.articleIndex < 0 && a.manifest != nil {
+		collectionID := ""
+		for _, item := range a.manifest.CollectionItems {
+			if item.Path == path {
+				collectionID = item.CollectionID
+				break
+			}
+		}
+
+		a.articleSiblings = nil
+		if collectionID != "" {
+			for _, item := range a.manifest.CollectionItems {
+				if item.CollectionID == collectionID {
+					a.articleSiblings = append(a.articleSiblings, item)
+				}
+			}
+			a.sortCollectionItems(a.articleSiblings)
+			a.articleSiblings = a.withoutDrafts(a.articleSiblings)
+			for i, item := range a.articleSiblings {
+				if item.Path == path {
+					a.articleIndex = i
+					break
+				}
+			}
+		}
+	}
+
+	// The keys do nothing at either end of the collection, or on pages
+	// that aren't in one
+	keys.NextArticle.SetEnabled(a.articleIndex >= 0 && a.articleIndex < len(a.articleSiblings)-1)
+	keys.PrevArticle.SetEnabled(a.articleIndex > 0)
+}
+
+// openAdjacentArticle opens the item delta places from the one being viewed
+func (a *App) openAdjacentArticle(delta int) (tea.Model, tea.Cmd) {
+	index := a.articleIndex + delta
+	if a.articleIndex < 0 || index < 0 || index >= len(a.articleSiblings) {
+		return a, nil
+	}
+	a.beginLoading()
+	return a, a.loadContent(a.articleSiblings[index].Path)
+}
+
+// articleHelp describes the next and previous item keys, when either applies
+func (a *App) articleHelp() string {
+	switch {
+	case keys.PrevArticle.Enabled() && keys.NextArticle.Enabled():
+		return tr(" • [/]: prev/next item")
+	case keys.NextArticle.Enabled():
+		return tr(" • ]: next item")
+	case keys.PrevArticle.Enabled():
+		return tr(" • [: prev item")
+	}
+	return ""
+}
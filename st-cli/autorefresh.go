@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"st-cli/pkg/sparktype"
+)
+
+// AutoRefreshMsg starts a background check of the site for changes
+type AutoRefreshMsg struct{}
+
+// AutoRefreshedMsg carries the result of a background check. content is nil
+// unless a page was being viewed when the check started.
+type AutoRefreshedMsg struct {
+	poll     bool // The check was made by --refresh-interval
+	manifest *sparktype.SiteManifest
+	path     string
+	content  *sparktype.ContentFile
+	err      error
+}
+
+// scheduleAutoRefresh waits for the refresh interval before the next check,
+// or does nothing if auto-refresh is off
+func (a *App) scheduleAutoRefresh() tea.Cmd {
+	if a.refreshInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(a.refreshInterval, func(time.Time) tea.Msg {
+		return AutoRefreshMsg{}
+	})
+}
+
+// autoRefresh refetches the manifest and the page being viewed in the
+// background, revalidating anything the disk cache holds. poll is set for
+// the periodic checks, which schedule the next one when they finish.
+func (a *App) autoRefresh(poll bool) tea.Cmd {
+	path := ""
+	if a.state == StateContentView {
+		path = a.currentPath
+	}
+	client := a.client.Revalidating()
+
+	return func() tea.Msg {
+		manifest, err := client.FetchManifest(a.ctx)
+		if err != nil {
+			return AutoRefreshedMsg{poll: poll, err: err}
+		}
+
+		msg := AutoRefreshedMsg{poll: poll, manifest: manifest, path: path}
+		if path != "" {
+			content, err := client.FetchContent(a.ctx, path)
+			if err != nil {
+				return AutoRefreshedMsg{poll: poll, err: err}
+			}
+			a.renderer.PrefetchImages(a.ctx, content)
+			msg.content = content
+		}
+		return msg
+	}
+}
+
+// handleAutoRefreshed applies a background check's result if anything has
+// changed, leaving the screen alone otherwise. Failed checks are dropped
+// silently; the next one may succeed.
+func (a *App) handleAutoRefreshed(msg AutoRefreshedMsg) (tea.Model, tea.Cmd) {
+	var next tea.Cmd
+	if msg.poll {
+		next = a.scheduleAutoRefresh()
+	}
+
+	// Don't disturb a load, search or prompt the user is in the middle of
+	busy := a.state == StateLoading || a.state == StateIndexing || a.state == StateSearch || a.state == StateError || a.state == StateNotFound ||
+		a.findActive || a.pageInputActive
+	if msg.err != nil || busy {
+		return a, next
+	}
+
+	changed := false
+	if !reflect.DeepEqual(msg.manifest, a.manifest) {
+		changed = true
+		// The pages listed may have changed too, so they're fetched again
+		a.client.ForgetContent()
+		a.applyManifestUpdate(msg.manifest)
+	}
+	if msg.content != nil && a.state == StateContentView && msg.path == a.currentPath &&
+		!reflect.DeepEqual(msg.content, a.content) {
+		changed = true
+		a.content = msg.content
+		a.contentWords = a.renderer.WordCount(a.content.Content)
+		offset := a.viewport.YOffset
+		a.setupContentView()
+		a.viewport.SetYOffset(offset)
+	}
+
+	if !changed {
+		return a, next
+	}
+	return a, tea.Batch(next, a.setStatus(tr("Updated from the site")))
+}
+
+// applyManifestUpdate replaces the manifest, rebuilding the menu and any
+// collection listing while keeping the selection where it was
+func (a *App) applyManifestUpdate(manifest *sparktype.SiteManifest) {
+	a.manifest = manifest
+	a.applySiteAccent()
+	a.checkGeneratorVersion()
+	index := a.list.Index()
+	a.buildNavigationItems()
+
+	switch a.state {
+	case StateMainMenu:
+		a.setupUI()
+		a.list.Select(index)
+
+	case StateCollectionListing:
+		page, tag := a.currentPage, a.tagFilter
+		a.showCollectionListing(a.collectionID, a.collectionTitle)
+		a.tagFilter = tag
+		a.applyCollectionFilter()
+		if page <= a.totalPages {
+			a.currentPage = page
+		}
+		a.setupCollectionListingUI()
+		a.list.Select(index)
+	}
+}
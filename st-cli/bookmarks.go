@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// Bookmark is a saved page or collection item
+type Bookmark struct {
+	Title string `json:"title"`
+	Path  string `json:"path"`
+}
+
+// BookmarkItem wraps a bookmark for the list component
+type BookmarkItem struct {
+	Bookmark Bookmark
+}
+
+// Title returns the title of the bookmarked content
+func (b BookmarkItem) Title() string {
+	return b.Bookmark.Title
+}
+
+// Description returns the content path of the bookmark
+func (b BookmarkItem) Description() string {
+	return b.Bookmark.Path
+}
+
+// FilterValue returns the value to filter on
+func (b BookmarkItem) FilterValue() string {
+	return b.Bookmark.Title
+}
+
+// bookmarkStore holds the bookmarks for every site, keyed by site URL
+type bookmarkStore struct {
+	path  string
+	sites map[string][]Bookmark
+}
+
+// loadBookmarks reads the bookmarks file at path. A missing or unreadable
+// file gives an empty store, which replaces the file when next saved.
+func loadBookmarks(path string) *bookmarkStore {
+	store := &bookmarkStore{path: path, sites: make(map[string][]Bookmark)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return store
+	}
+	if err := json.Unmarshal(data, &store.sites); err != nil || store.sites == nil {
+		store.sites = make(map[string][]Bookmark)
+	}
+	return store
+}
+
+// list returns the bookmarks for a site, oldest first
+func (s *bookmarkStore) list(site string) []Bookmark {
+	return s.sites[site]
+}
+
+// has reports whether a path is bookmarked on a site
+func (s *bookmarkStore) has(site, path string) bool {
+	for _, bookmark := range s.sites[site] {
+		if bookmark.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// toggle adds the bookmark to a site, or removes it if its path is already
+// bookmarked, and saves the file. It reports whether the bookmark was added.
+func (s *bookmarkStore) toggle(site string, bookmark Bookmark) (bool, error) {
+	bookmarks := s.sites[site]
+	added := true
+	for i, existing := range bookmarks {
+		if existing.Path == bookmark.Path {
+			bookmarks = append(bookmarks[:i:i], bookmarks[i+1:]...)
+			added = false
+			break
+		}
+	}
+	if added {
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	if len(bookmarks) == 0 {
+		delete(s.sites, site)
+	} else {
+		s.sites[site] = bookmarks
+	}
+	return added, s.save()
+}
+
+// save writes the bookmarks file
+func (s *bookmarkStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.sites, "", "  ")
+	if err != nil {
+		return err
+	}
+	return sparktype.WriteFileAtomic(s.path, data)
+}
+
+// toggleBookmark bookmarks the content at path, or removes its bookmark
+func (a *App) toggleBookmark(title, path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+
+	added, err := a.bookmarks.toggle(a.client.GetBaseURL(), Bookmark{Title: title, Path: path})
+	if err != nil {
+		return a.setStatus(fmt.Sprintf(tr("Could not save bookmarks: %v"), err))
+	}
+	if added {
+		return a.setStatus(fmt.Sprintf(tr("Bookmarked %s"), title))
+	}
+	return a.setStatus(fmt.Sprintf(tr("Removed bookmark for %s"), title))
+}
+
+// showBookmarks opens the list of bookmarks for the site
+func (a *App) showBookmarks() (tea.Model, tea.Cmd) {
+	bookmarks := a.bookmarks.list(a.client.GetBaseURL())
+	if len(bookmarks) == 0 {
+		return a, a.setStatus(tr("No bookmarks yet; press b on a page to add one"))
+	}
+
+	items := make([]list.Item, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		items[i] = BookmarkItem{Bookmark: bookmark}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.bookmarkList = list.New(items, delegate, a.width, a.height-4)
+	a.bookmarkList.Title = tr("Bookmarks")
+	a.bookmarkList.SetShowStatusBar(false)
+	a.bookmarkList.SetShowHelp(false)
+
+	a.bookmarksReturnState = a.state
+	a.state = StateBookmarks
+	return a, nil
+}
+
+// openBookmark loads the bookmarked content
+func (a *App) openBookmark() (tea.Model, tea.Cmd) {
+	item, ok := a.bookmarkList.SelectedItem().(BookmarkItem)
+	if !ok {
+		return a, nil
+	}
+	a.beginLoading()
+	return a, a.loadContent(item.Bookmark.Path)
+}
+
+// removeSelectedBookmark removes the selected bookmark from the list
+func (a *App) removeSelectedBookmark() (tea.Model, tea.Cmd) {
+	item, ok := a.bookmarkList.SelectedItem().(BookmarkItem)
+	if !ok {
+		return a, nil
+	}
+
+	cmd := a.toggleBookmark(item.Bookmark.Title, item.Bookmark.Path)
+	a.bookmarkList.RemoveItem(a.bookmarkList.Index())
+	if len(a.bookmarkList.Items()) == 0 {
+		a.state = a.bookmarksReturnState
+	}
+	return a, cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// breadcrumbSeparator goes between the entries of the breadcrumb trail
+const breadcrumbSeparator = " › "
+
+var (
+	breadcrumbStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#626262"))
+
+	breadcrumbCurrentStyle = lipgloss.NewStyle().
+				Foreground(accentColor).
+				Bold(true)
+)
+
+// pageTrail returns the titles of the pages leading to the page at path in
+// the site structure, ending with the page itself, or nil if it isn't there
+func (a *App) pageTrail(path string) []string {
+	var find func(items []sparktype.MenuItem) []string
+	find = func(items []sparktype.MenuItem) []string {
+		for _, item := range items {
+			if item.Path == path {
+				return []string{item.Title}
+			}
+			if trail := find(item.Children); trail != nil {
+				return append([]string{item.Title}, trail...)
+			}
+		}
+		return nil
+	}
+	return find(a.manifest.Structure)
+}
+
+// listingTrail returns the trail to the collection listing being shown
+func (a *App) listingTrail() []string {
+	if trail := a.pageTrail(a.collectionPath); trail != nil {
+		return trail
+	}
+	return []string{a.collectionTitle}
+}
+
+// breadcrumbs returns the trail of titles leading to the current view
+func (a *App) breadcrumbs() []string {
+	if a.manifest == nil {
+		return nil
+	}
+
+	trail := []string{tr("Home")}
+	switch a.state {
+	case StateMainMenu:
+		if a.inSubmenu() && len(a.navigationItems) > 0 {
+			// The first entry of a submenu is the page it belongs to
+			trail = append(trail, a.pageTrail(a.navigationItems[0].Path)...)
+		}
+
+	case StateCollectionListing, StateTagFilter:
+		trail = append(trail, a.listingTrail()...)
+
+	case StateContentView, StateTOC, StateLinks:
+		item, _ := a.lookupPath(a.currentPath)
+		if item == nil {
+			if pages := a.pageTrail(a.currentPath); pages != nil {
+				return append(trail, pages...)
+			}
+		} else if item.CollectionID == a.collectionID {
+			trail = append(trail, a.listingTrail()...)
+		} else {
+			for _, collection := range a.manifest.Collections {
+				if collection.ID == item.CollectionID {
+					trail = append(trail, collection.Name)
+				}
+			}
+		}
+		if a.content != nil {
+			trail = append(trail, a.content.Title)
+		}
+	}
+	return trail
+}
+
+// breadcrumbView renders the breadcrumb trail, or "" at the top level
+func (a *App) breadcrumbView() string {
+	trail := a.breadcrumbs()
+	if len(trail) < 2 {
+		return ""
+	}
+	for i := range trail {
+		trail[i] = a.renderer.Emojify(trail[i])
+	}
+
+	// Drop the oldest entries when the trail is too wide for the window
+	for start := 0; start < len(trail)-1; start++ {
+		line := renderTrail(trail[start:], start > 0)
+		if a.width == 0 || lipgloss.Width(line) <= a.width {
+			return line
+		}
+	}
+	return renderTrail(trail[len(trail)-1:], true)
+}
+
+// renderTrail styles a breadcrumb trail, marking it as shortened if the
+// start of it was dropped
+func renderTrail(trail []string, shortened bool) string {
+	var parts []string
+	if shortened {
+		parts = append(parts, breadcrumbStyle.Render("…"))
+	}
+	for i, title := range trail {
+		if i == len(trail)-1 {
+			parts = append(parts, breadcrumbCurrentStyle.Render(title))
+		} else {
+			parts = append(parts, breadcrumbStyle.Render(title))
+		}
+	}
+	return strings.Join(parts, breadcrumbStyle.Render(breadcrumbSeparator))
+}
+
+// withBreadcrumbs puts the breadcrumb trail above a view. A notice that the
+// site couldn't be reached, or a warning about the site until it is
+// dismissed, takes the trail's place.
+func (a *App) withBreadcrumbs(view string) string {
+	if banner := a.cachedBanner(); banner != "" {
+		return banner + "\n" + view
+	}
+	if banner := a.warningBanner(); banner != "" {
+		return banner + "\n" + view
+	}
+	if trail := a.breadcrumbView(); trail != "" {
+		return trail + "\n" + view
+	}
+	return view
+}
+
+// cachedBanner tells the user that the site couldn't be reached and what is
+// shown is a cached copy, or returns "" when it is current
+func (a *App) cachedBanner() string {
+	if a.cachedAt.IsZero() {
+		return ""
+	}
+	text := "⚠ " + fmt.Sprintf(tr("Offline — showing cached copy from %s"), formatDate(a.cachedAt.Local(), "2 Jan 2006 15:04"))
+	return warningStyle.Render(text + " • " + tr("r: retry"))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"st-cli/pkg/sparktype"
+)
+
+// isTerminal reports whether output written to w goes to a terminal. Only
+// files can be terminals; buffers and other writers never are.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// catPath normalises a content path given on the command line, accepting
+// paths with or without the leading "/_site/"
+func catPath(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	return strings.TrimPrefix(path, "_site/")
+}
+
+// catFlags registers the flags of `st-cli cat`
+func catFlags(fs *flag.FlagSet, config *Config) (raw *bool) {
+	raw = fs.Bool("raw", false, "print the markdown source instead of rendering it")
+	config.RegisterRenderFlags(fs)
+	config.RegisterClientFlags(fs)
+	return raw
+}
+
+// runCat implements `st-cli cat`, printing one content file to out
+func runCat(args []string, out io.Writer) int {
+	config := DefaultConfig()
+	fs := flag.NewFlagSet("cat", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: st-cli cat [flags] <site-url> <path>")
+		fs.PrintDefaults()
+	}
+	// Output going to a file or a pipe is plain unless colours are asked for
+	if !isTerminal(out) {
+		config.NoColor = true
+	}
+	raw := catFlags(fs, &config)
+	parseFlags(fs, args)
+	setLocale(config.Lang)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	siteURL, path := fs.Arg(0), catPath(fs.Arg(1))
+
+	client, err := config.NewClient(siteURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	content, err := client.FetchContent(context.Background(), path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := writeContent(out, config, client, content, *raw); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeContent writes a content file to out, rendered with config's options
+// or, if raw is set, as its markdown source
+func writeContent(out io.Writer, config Config, client *sparktype.Client, content *sparktype.ContentFile, raw bool) error {
+	var output string
+	var err error
+	if raw {
+		output, err = markdownDocument(content)
+	} else {
+		opts := config.RendererOptions(client)
+		if !config.NoColor {
+			// Images are drawn straight to the terminal, so only here and
+			// not in the browser, whose redraws would garble them
+			opts = append(opts, sparktype.WithInlineImages(client.FetchImage))
+		}
+
+		var renderer *sparktype.ContentRenderer
+		renderer, err = sparktype.NewContentRenderer(opts...)
+		if err == nil {
+			output, err = renderer.RenderContent(content)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(out, output)
+	return err
+}
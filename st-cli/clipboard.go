@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyToClipboard puts text on the system clipboard, using the clipboard
+// tool the platform provides
+func copyToClipboard(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	case "windows":
+		cmd = exec.Command("clip")
+	default:
+		// Wayland and X11 each have their own tools, and any may be missing
+		switch {
+		case os.Getenv("WAYLAND_DISPLAY") != "" && hasCommand("wl-copy"):
+			cmd = exec.Command("wl-copy")
+		case hasCommand("xclip"):
+			cmd = exec.Command("xclip", "-selection", "clipboard")
+		case hasCommand("xsel"):
+			cmd = exec.Command("xsel", "--clipboard", "--input")
+		default:
+			return errors.New("no clipboard tool found; install wl-clipboard, xclip or xsel")
+		}
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// hasCommand reports whether a program is on the PATH
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// copyURL copies the address of the page being viewed on the site
+func (a *App) copyURL() (tea.Model, tea.Cmd) {
+	url := a.urlForPath(a.currentPath)
+	if url == "" {
+		return a, a.setStatus(tr("No URL available for this page"))
+	}
+	if err := copyToClipboard(url); err != nil {
+		return a, a.setStatus(fmt.Sprintf(tr("Could not copy the URL: %v"), err))
+	}
+	return a, a.setStatus(fmt.Sprintf(tr("Copied %s"), url))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listColumnWidth is the narrowest a column of the menu or a listing is
+// drawn; windows wide enough for more than one are split into columns
+const listColumnWidth = 50
+
+// maxListColumns is the most columns a list is split into
+const maxListColumns = 3
+
+// listColumns returns how many columns the menu or listing being shown is
+// drawn in. The tree and year-grouped listings stay in one column, since
+// their indentation and headings read down the page.
+func (a *App) listColumns() int {
+	switch {
+	case a.state == StateMainMenu && !a.treeMode:
+	case a.state == StateCollectionListing && !a.groupByYear:
+	default:
+		return 1
+	}
+	if a.listDelegate == nil || len(a.list.VisibleItems()) == 0 {
+		return 1
+	}
+
+	columns := a.width / listColumnWidth
+	if columns > maxListColumns {
+		columns = maxListColumns
+	}
+	if columns < 1 {
+		return 1
+	}
+	return columns
+}
+
+// listTitleView renders the list's title, or its filter input while a
+// filter is being typed
+func (a *App) listTitleView() string {
+	title := a.list.Styles.Title.Render(a.list.Title)
+	if a.list.SettingFilter() {
+		title = a.list.FilterInput.View()
+	}
+	return a.list.Styles.TitleBar.Render(title)
+}
+
+// listRows returns how many items fit in each column below the title
+func (a *App) listRows() int {
+	// The list is given the window less the breadcrumbs and help line, and
+	// a line is kept for the page dots
+	height := a.height - 4 - lipgloss.Height(a.listTitleView()) - 1
+	rows := (height + a.listDelegate.Spacing()) / (a.listDelegate.Height() + a.listDelegate.Spacing())
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
+
+// columnsView renders the menu or listing in columns, filled top to bottom
+// and then left to right, showing the page of them holding the selection
+func (a *App) columnsView(columns int) string {
+	items := a.list.VisibleItems()
+	rows := a.listRows()
+	perPage := rows * columns
+	page := a.list.Index() / perPage
+
+	// The delegate truncates to the list's width, so it's given a list
+	// the width of a column to draw with
+	width := a.width / columns
+	column := a.list
+	column.SetWidth(width)
+
+	var rendered []string
+	for c := 0; c < columns; c++ {
+		start := page*perPage + c*rows
+		if start >= len(items) {
+			break
+		}
+		end := start + rows
+		if end > len(items) {
+			end = len(items)
+		}
+
+		var builder strings.Builder
+		for i := start; i < end; i++ {
+			a.listDelegate.Render(&builder, column, i, items[i])
+			if i != end-1 {
+				builder.WriteString(strings.Repeat("\n", a.listDelegate.Spacing()+1))
+			}
+		}
+		rendered = append(rendered, lipgloss.NewStyle().Width(width).Render(builder.String()))
+	}
+
+	sections := []string{a.listTitleView(), lipgloss.JoinHorizontal(lipgloss.Top, rendered...)}
+	if pages := (len(items) + perPage - 1) / perPage; pages > 1 {
+		dots := a.list.Paginator
+		dots.PerPage = perPage
+		dots.SetTotalPages(len(items))
+		dots.Page = page
+		sections = append(sections, a.list.Styles.PaginationStyle.Render(dots.View()))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// listView renders the menu or listing, in columns when the window is wide
+// enough for them
+func (a *App) listView() string {
+	if columns := a.listColumns(); columns > 1 {
+		return a.columnsView(columns)
+	}
+	return a.list.View()
+}
+
+// moveColumn moves the selection to the same row of the next column to the
+// right (delta 1) or left (delta -1). It reports false, leaving the
+// selection alone, when there is no column that way on the page.
+func (a *App) moveColumn(delta int) bool {
+	columns := a.listColumns()
+	if columns < 2 {
+		return false
+	}
+	rows := a.listRows()
+	perPage := rows * columns
+	index := a.list.Index()
+	pageStart := index / perPage * perPage
+
+	target := index + delta*rows
+	count := len(a.list.VisibleItems())
+	if target < pageStart || target >= pageStart+perPage {
+		return false
+	}
+	if target >= count {
+		// The next column is shorter; go to its last item
+		if (count-1-pageStart)/rows == (index-pageStart)/rows {
+			return false
+		}
+		target = count - 1
+	}
+	a.list.Select(target)
+	return true
+}
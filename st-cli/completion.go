@@ -0,0 +1,294 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/styles"
+)
+
+// completionShells are the shells `st-cli completion` writes scripts for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// commandFlags registers the flags of each subcommand, keyed by name, with
+// "" for the browser. Completion scripts are built from these so they list
+// the same flags the commands accept.
+var commandFlags = map[string]func(fs *flag.FlagSet, config *Config){
+	"":           browserFlags,
+	"cat":        func(fs *flag.FlagSet, config *Config) { catFlags(fs, config) },
+	"list":       func(fs *flag.FlagSet, config *Config) { config.RegisterClientFlags(fs) },
+	"export":     func(fs *flag.FlagSet, config *Config) { config.RegisterClientFlags(fs) },
+	"feed":       func(fs *flag.FlagSet, config *Config) { feedFlags(fs, config) },
+	"snapshot":   func(fs *flag.FlagSet, config *Config) { config.RegisterClientFlags(fs) },
+	"completion": func(fs *flag.FlagSet, config *Config) {},
+}
+
+// completionFlag is a flag offered by a completion script
+type completionFlag struct {
+	Name     string
+	Usage    string
+	TakesArg bool     // The flag is followed by a value
+	Values   []string // Values to complete after the flag; none means files
+}
+
+// completionCommand is a subcommand and the flags it accepts
+type completionCommand struct {
+	Name  string
+	Flags []completionFlag
+}
+
+// flagValues lists the values worth completing for flags with a fixed set
+var flagValues = map[string]func() []string{
+	"theme":      func() []string { return append([]string{"auto"}, themeNames()...) },
+	"code-theme": styles.Names,
+	"format":     func() []string { return []string{"rss", "atom"} },
+	"lang":       Locales,
+}
+
+// completionCommands returns the subcommands, sorted by name after the
+// browser, with the flags each registers
+func completionCommands() []completionCommand {
+	names := make([]string, 0, len(commandFlags))
+	for name := range commandFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	commands := make([]completionCommand, 0, len(names))
+	for _, name := range names {
+		config := DefaultConfig()
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		commandFlags[name](fs, &config)
+
+		command := completionCommand{Name: name}
+		fs.VisitAll(func(f *flag.Flag) {
+			cf := completionFlag{Name: f.Name, Usage: f.Usage, TakesArg: true}
+			if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+				cf.TakesArg = false
+			}
+			if values, ok := flagValues[f.Name]; ok {
+				cf.Values = values()
+			}
+			command.Flags = append(command.Flags, cf)
+		})
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// subcommandNames returns the names of the subcommands, leaving out the browser
+func subcommandNames(commands []completionCommand) []string {
+	var names []string
+	for _, command := range commands {
+		if command.Name != "" {
+			names = append(names, command.Name)
+		}
+	}
+	return names
+}
+
+// flagNames returns the flags of a command written as --name
+func flagNames(flags []completionFlag) []string {
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = "--" + f.Name
+	}
+	return names
+}
+
+// valueFlags returns the flags that are followed by a value, grouped by the
+// values to complete for them, as "--a|--b" patterns for a shell case
+func valueFlags(commands []completionCommand) (patterns []string, values map[string][]string) {
+	seen := map[string]bool{}
+	values = map[string][]string{}
+	for _, command := range commands {
+		for _, f := range command.Flags {
+			if !f.TakesArg || seen[f.Name] {
+				continue
+			}
+			seen[f.Name] = true
+			key := strings.Join(f.Values, " ")
+			if _, ok := values[key]; !ok {
+				patterns = append(patterns, key)
+			}
+			values[key] = append(values[key], "--"+f.Name, "-"+f.Name)
+		}
+	}
+	return patterns, values
+}
+
+// bashCompletion writes a bash completion script
+func bashCompletion(commands []completionCommand) string {
+	var b strings.Builder
+	b.WriteString("# bash completion for st-cli\n")
+	b.WriteString("# Load it with: source <(st-cli completion bash)\n\n")
+	b.WriteString("_st_cli() {\n")
+	b.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("\tlocal command=\"\" flags=\"\"\n")
+	b.WriteString("\tif [ \"$COMP_CWORD\" -gt 1 ]; then\n\t\tcommand=\"${COMP_WORDS[1]}\"\n\tfi\n\n")
+
+	// A value follows flags that take one
+	patterns, values := valueFlags(commands)
+	b.WriteString("\tcase \"$prev\" in\n")
+	for _, key := range patterns {
+		fmt.Fprintf(&b, "\t%s)\n", strings.Join(values[key], "|"))
+		if key == "" {
+			b.WriteString("\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n\t\treturn\n\t\t;;\n")
+		} else {
+			fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n\t\treturn\n\t\t;;\n", key)
+		}
+	}
+	b.WriteString("\tesac\n\n")
+
+	b.WriteString("\tcase \"$command\" in\n")
+	for _, command := range commands {
+		if command.Name == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s)\n", command.Name)
+		if command.Name == "completion" {
+			fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n\t\treturn\n\t\t;;\n", strings.Join(completionShells, " "))
+			continue
+		}
+		fmt.Fprintf(&b, "\t\tflags=\"%s\"\n\t\t;;\n", strings.Join(flagNames(command.Flags), " "))
+	}
+	b.WriteString("\t*)\n")
+	fmt.Fprintf(&b, "\t\tflags=\"%s\"\n", strings.Join(flagNames(commands[0].Flags), " "))
+	b.WriteString("\t\tif [ \"$COMP_CWORD\" -eq 1 ] && [[ \"$cur\" != -* ]]; then\n")
+	fmt.Fprintf(&b, "\t\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\") $(compgen -f -- \"$cur\"))\n", strings.Join(subcommandNames(commands), " "))
+	b.WriteString("\t\t\treturn\n\t\tfi\n\t\t;;\n")
+	b.WriteString("\tesac\n\n")
+
+	b.WriteString("\tif [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("\t\tCOMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n")
+	b.WriteString("\telse\n")
+	b.WriteString("\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("\tfi\n")
+	b.WriteString("}\n\n")
+	b.WriteString("complete -o filenames -F _st_cli st-cli\n")
+	return b.String()
+}
+
+// zshCompletion writes a zsh completion script
+func zshCompletion(commands []completionCommand) string {
+	var b strings.Builder
+	b.WriteString("#compdef st-cli\n")
+	b.WriteString("# zsh completion for st-cli\n")
+	b.WriteString("# Load it with: source <(st-cli completion zsh)\n\n")
+	b.WriteString("_st_cli() {\n")
+	b.WriteString("\tlocal command=\"\"\n")
+	b.WriteString("\tlocal -a flags\n")
+	b.WriteString("\t(( CURRENT > 2 )) && command=\"${words[2]}\"\n\n")
+
+	patterns, values := valueFlags(commands)
+	b.WriteString("\tcase \"${words[CURRENT-1]}\" in\n")
+	for _, key := range patterns {
+		fmt.Fprintf(&b, "\t%s)\n", strings.Join(values[key], "|"))
+		if key == "" {
+			b.WriteString("\t\t_files\n\t\treturn\n\t\t;;\n")
+		} else {
+			fmt.Fprintf(&b, "\t\tcompadd -- %s\n\t\treturn\n\t\t;;\n", key)
+		}
+	}
+	b.WriteString("\tesac\n\n")
+
+	b.WriteString("\tcase \"$command\" in\n")
+	for _, command := range commands {
+		if command.Name == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s)\n", command.Name)
+		if command.Name == "completion" {
+			fmt.Fprintf(&b, "\t\tcompadd -- %s\n\t\treturn\n\t\t;;\n", strings.Join(completionShells, " "))
+			continue
+		}
+		fmt.Fprintf(&b, "\t\tflags=(%s)\n\t\t;;\n", strings.Join(flagNames(command.Flags), " "))
+	}
+	b.WriteString("\t*)\n")
+	fmt.Fprintf(&b, "\t\tflags=(%s)\n", strings.Join(flagNames(commands[0].Flags), " "))
+	b.WriteString("\t\tif (( CURRENT == 2 )) && [[ \"$PREFIX\" != -* ]]; then\n")
+	fmt.Fprintf(&b, "\t\t\tcompadd -- %s\n", strings.Join(subcommandNames(commands), " "))
+	b.WriteString("\t\t\t_files\n\t\t\treturn\n\t\tfi\n\t\t;;\n")
+	b.WriteString("\tesac\n\n")
+
+	b.WriteString("\tif [[ \"$PREFIX\" == -* ]]; then\n")
+	b.WriteString("\t\tcompadd -- $flags\n")
+	b.WriteString("\telse\n")
+	b.WriteString("\t\t_files\n")
+	b.WriteString("\tfi\n")
+	b.WriteString("}\n\n")
+	b.WriteString("compdef _st_cli st-cli\n")
+	return b.String()
+}
+
+// fishQuote quotes a string for fish, which only treats \' and \\ as
+// escapes inside single quotes
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// fishCompletion writes a fish completion script
+func fishCompletion(commands []completionCommand) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for st-cli\n")
+	b.WriteString("# Load it with: st-cli completion fish | source\n\n")
+
+	subcommands := strings.Join(subcommandNames(commands), " ")
+	fmt.Fprintf(&b, "complete -c st-cli -n __fish_use_subcommand -a %s\n", fishQuote(subcommands))
+
+	for _, command := range commands {
+		condition := "__fish_seen_subcommand_from " + command.Name
+		if command.Name == "" {
+			condition = "not __fish_seen_subcommand_from " + subcommands
+		}
+		if command.Name == "completion" {
+			fmt.Fprintf(&b, "complete -c st-cli -n %s -f -a %s\n", fishQuote(condition), fishQuote(strings.Join(completionShells, " ")))
+			continue
+		}
+		for _, f := range command.Flags {
+			fmt.Fprintf(&b, "complete -c st-cli -n %s -l %s", fishQuote(condition), f.Name)
+			switch {
+			case len(f.Values) > 0:
+				fmt.Fprintf(&b, " -x -a %s", fishQuote(strings.Join(f.Values, " ")))
+			case f.TakesArg:
+				b.WriteString(" -r")
+			}
+			fmt.Fprintf(&b, " -d %s\n", fishQuote(f.Usage))
+		}
+	}
+	return b.String()
+}
+
+// runCompletion implements `st-cli completion`, printing a shell completion
+// script for st-cli to out
+func runCompletion(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("completion", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: st-cli completion <%s>\n", strings.Join(completionShells, "|"))
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	commands := completionCommands()
+	switch fs.Arg(0) {
+	case "bash":
+		fmt.Fprint(out, bashCompletion(commands))
+	case "zsh":
+		fmt.Fprint(out, zshCompletion(commands))
+	case "fish":
+		fmt.Fprint(out, fishCompletion(commands))
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown shell %q (use %s)\n", fs.Arg(0), strings.Join(completionShells, ", "))
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/styles"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+
+	"st-cli/pkg/sparktype"
+)
+
+// Config holds the user-configurable settings for the application
+type Config struct {
+	// TOCMinHeadings is the number of headings a page needs before a table
+	// of contents is prepended to it; 0 disables the inline table of contents
+	TOCMinHeadings int
+
+	// Theme is the name of a glamour standard style, or "auto" to follow
+	// the terminal background
+	Theme string
+
+	// CodeTheme is a chroma style for code blocks; empty uses the colours
+	// from the theme
+	CodeTheme string
+
+	// Wrap is the column at which content wraps; 0 disables wrapping and a
+	// negative value follows the window width
+	Wrap int
+
+	// ImagePreview draws images as coloured blocks instead of placeholders
+	ImagePreview bool
+
+	// StyleFile is a glamour JSON style file; when set it replaces Theme
+	StyleFile string
+
+	// NoColor renders content as plain text and drops colours from the
+	// browser's own styling
+	NoColor bool
+
+	// Lang is the language of the interface; empty follows the environment
+	Lang string
+
+	// NoEmoji leaves emoji shortcodes such as :rocket: as they are written
+	NoEmoji bool
+
+	// NoFootnotes leaves footnotes such as [^1] as they are written instead
+	// of collecting them into notes at the end of the page
+	NoFootnotes bool
+
+	// Typographer turns straight quotes, dashes and dots into typographic
+	// punctuation
+	Typographer bool
+
+	// AllowHTML renders the formatting in HTML embedded in pages instead of
+	// reducing it to plain text
+	AllowHTML bool
+
+	// PageSize is the number of collection items on each page of a listing
+	PageSize int
+
+	// Drafts lists collection items marked as drafts, which are hidden
+	// otherwise
+	Drafts bool
+
+	// RefreshInterval is how often the browser checks the site for changes;
+	// 0 disables checking
+	RefreshInterval time.Duration
+
+	// CacheDir is the root directory for cached responses
+	CacheDir string
+
+	// CacheTTL is how long cached responses are served without refetching;
+	// 0 disables the disk cache
+	CacheTTL time.Duration
+
+	// Timeout bounds each HTTP request made to the site
+	Timeout time.Duration
+
+	// Concurrency is the most requests in flight to the site at once
+	Concurrency int
+
+	// Retries is the number of attempts made for requests that fail with a
+	// connection error or a 5xx response; RetryDelay is the initial backoff
+	Retries    int
+	RetryDelay time.Duration
+
+	// Username and Password are HTTP basic auth credentials; when empty,
+	// credentials embedded in the site URL are used instead
+	Username string
+	Password string
+
+	// Token is a bearer token sent with every request; it wins over basic auth
+	Token string
+
+	// Headers are extra headers sent with every request
+	Headers http.Header
+
+	// Verbose writes a debug log of every request to LogFile, or to stderr
+	// when no file is given
+	Verbose bool
+	LogFile string
+
+	// ManifestPath is where the site keeps its manifest, overriding the
+	// standard locations
+	ManifestPath string
+
+	// ContentPrefix is the directory content paths in the manifest are
+	// relative to
+	ContentPrefix string
+}
+
+// DefaultConfig returns the configuration used when no flags are given
+func DefaultConfig() Config {
+	return Config{
+		TOCMinHeadings: 0,
+		Theme:          "auto",
+		Wrap:           -1,
+		PageSize:       10,
+		CacheDir:       sparktype.DefaultCacheDir(),
+		CacheTTL:       5 * time.Minute,
+		Timeout:        sparktype.DefaultTimeout,
+		Concurrency:    sparktype.DefaultConcurrency,
+		Retries:        3,
+		RetryDelay:     100 * time.Millisecond,
+		Token:          os.Getenv("ST_TOKEN"),
+		Headers:        http.Header{},
+		ContentPrefix:  sparktype.DefaultContentPrefix,
+		NoColor:        os.Getenv("NO_COLOR") != "", // See https://no-color.org
+	}
+}
+
+// DefaultConfigDir returns the directory st-cli keeps its settings and
+// bookmarks in
+func DefaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "st-cli")
+}
+
+// RegisterRenderFlags registers the flags that configure how content is
+// rendered. They are shared by the browser and `st-cli cat`.
+func (c *Config) RegisterRenderFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.TOCMinHeadings, "toc", c.TOCMinHeadings, "prepend a table of contents to pages with at least this many headings (0 disables)")
+	fs.Func("theme", "colour theme: auto, "+strings.Join(themeNames(), ", ")+" (default auto)", func(value string) error {
+		if value != "auto" && glamour.DefaultStyles[value] == nil {
+			return fmt.Errorf("unknown theme %q", value)
+		}
+		c.Theme = value
+		return nil
+	})
+	fs.Func("code-theme", "chroma style for highlighting code blocks, such as monokai or github (default: from --theme)", func(value string) error {
+		if _, ok := styles.Registry[value]; !ok {
+			return fmt.Errorf("unknown code theme %q; available: %s", value, strings.Join(styles.Names(), ", "))
+		}
+		c.CodeTheme = value
+		return nil
+	})
+	fs.BoolVar(&c.NoColor, "no-color", c.NoColor, "render without colours or styling (default when $NO_COLOR is set)")
+	fs.Func("lang", "language of the interface: "+strings.Join(Locales(), ", ")+" (default: from $LANG)", func(value string) error {
+		if _, ok := catalogs[value]; !ok && value != defaultLocale {
+			return fmt.Errorf("unknown language %q; available: %s", value, strings.Join(Locales(), ", "))
+		}
+		c.Lang = value
+		return nil
+	})
+	fs.BoolVar(&c.NoEmoji, "no-emoji", c.NoEmoji, "show emoji shortcodes such as :rocket: as written instead of as emoji")
+	fs.BoolVar(&c.NoFootnotes, "no-footnotes", c.NoFootnotes, "show footnotes such as [^1] as written instead of as numbered notes")
+	fs.BoolVar(&c.AllowHTML, "allow-html", c.AllowHTML, "render bold, italic, struck out and code text in HTML embedded in pages instead of showing it as plain text")
+	fs.BoolVar(&c.Typographer, "typographer", c.Typographer, "use curly quotes, dashes and ellipses in place of \", -- and ...")
+	fs.BoolVar(&c.ImagePreview, "image-preview", c.ImagePreview, "fetch images and draw rough previews of them with coloured blocks")
+	fs.Func("wrap", "wrap content at this column, 0 disables wrapping (default: window width)", func(value string) error {
+		width, err := strconv.Atoi(value)
+		if err != nil || width < 0 {
+			return fmt.Errorf("must be a column number, or 0")
+		}
+		c.Wrap = width
+		return nil
+	})
+	fs.Func("style-file", "glamour JSON style file, overriding --theme", func(value string) error {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return fmt.Errorf("cannot read style file: %v", err)
+		}
+		var style ansi.StyleConfig
+		if err := json.Unmarshal(data, &style); err != nil {
+			return fmt.Errorf("%s is not a valid glamour style: %v", value, err)
+		}
+		c.StyleFile = value
+		return nil
+	})
+}
+
+// RegisterBrowserFlags registers the flags that only apply to the
+// interactive browser
+func (c *Config) RegisterBrowserFlags(fs *flag.FlagSet) {
+	fs.Func("page-size", "number of collection items on each page of a listing (default 10)", func(value string) error {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return fmt.Errorf("must be a positive number")
+		}
+		c.PageSize = size
+		return nil
+	})
+	fs.BoolVar(&c.Drafts, "drafts", c.Drafts, "list collection items marked as drafts or unpublished")
+	fs.DurationVar(&c.RefreshInterval, "refresh-interval", c.RefreshInterval, "check the site for changes this often, e.g. 30s (0 disables)")
+}
+
+// RendererOptions returns the renderer options for the configured settings,
+// rendering content from the client's site
+func (c Config) RendererOptions(client *sparktype.Client) []sparktype.RendererOption {
+	opts := []sparktype.RendererOption{
+		sparktype.WithBaseURL(client.GetBaseURL()),
+		sparktype.WithStyle(c.Theme),
+		sparktype.WithCodeTheme(c.CodeTheme),
+		sparktype.WithTOC(c.TOCMinHeadings),
+		sparktype.WithEmoji(!c.NoEmoji),
+		sparktype.WithFootnotes(!c.NoFootnotes),
+		sparktype.WithTypographer(c.Typographer),
+		sparktype.WithHTML(c.AllowHTML),
+		sparktype.WithTranslator(tr),
+	}
+	if c.StyleFile != "" {
+		opts = append(opts, sparktype.WithStyleFile(c.StyleFile))
+	}
+	if c.Wrap >= 0 {
+		opts = append(opts, sparktype.WithWordWrap(c.Wrap))
+	}
+	if c.ImagePreview {
+		opts = append(opts, sparktype.WithImagePreview(client.FetchImage))
+	}
+	if c.NoColor {
+		opts = append(opts, sparktype.WithStyle("notty"), sparktype.WithStyleFile(""), sparktype.WithCodeTheme(""))
+	}
+	return opts
+}
+
+// themeNames returns the names of glamour's standard styles, sorted
+func themeNames() []string {
+	names := make([]string, 0, len(glamour.DefaultStyles))
+	for name := range glamour.DefaultStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterClientFlags registers the flags that configure how the site is
+// fetched. They are shared by the browser and every subcommand.
+func (c *Config) RegisterClientFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.CacheDir, "cache-dir", c.CacheDir, "directory for cached site content")
+	fs.DurationVar(&c.CacheTTL, "cache-ttl", c.CacheTTL, "how long cached content is used before refetching (0 disables the cache)")
+	fs.DurationVar(&c.Timeout, "timeout", c.Timeout, "HTTP request timeout, e.g. 500ms, 5s or 2m")
+	fs.IntVar(&c.Concurrency, "concurrency", c.Concurrency, "most requests made to the site at once")
+	fs.IntVar(&c.Retries, "retries", c.Retries, "attempts made for requests failing with a connection error or 5xx response")
+	fs.DurationVar(&c.RetryDelay, "retry-delay", c.RetryDelay, "initial delay between retries, doubled after each attempt")
+	fs.Func("user", "HTTP basic auth credentials as user:pass", func(value string) error {
+		c.Username, c.Password, _ = strings.Cut(value, ":")
+		return nil
+	})
+	// A Func flag keeps the token from $ST_TOKEN out of the usage output
+	fs.Func("token", "bearer token sent with every request (defaults to $ST_TOKEN)", func(value string) error {
+		c.Token = value
+		return nil
+	})
+	fs.Func("header", "extra request header as \"Key: Value\" (repeatable)", func(spec string) error {
+		name, value, err := parseHeader(spec)
+		if err != nil {
+			return err
+		}
+		c.Headers.Add(name, value)
+		return nil
+	})
+	fs.StringVar(&c.ManifestPath, "manifest-path", c.ManifestPath, "path of the site manifest, e.g. /api/manifest.json, instead of the standard locations")
+	fs.StringVar(&c.ContentPrefix, "content-prefix", c.ContentPrefix, "directory content paths are relative to, e.g. / for the site root")
+	fs.BoolVar(&c.Verbose, "verbose", c.Verbose, "log each request, its status and timing, and the manifest location chosen")
+	fs.BoolVar(&c.Verbose, "v", c.Verbose, "shorthand for --verbose")
+	fs.StringVar(&c.LogFile, "log-file", c.LogFile, "append the --verbose log to this file instead of stderr (implies --verbose)")
+}
+
+// DefaultLogFile returns the file the browser writes its --verbose log to
+// when no --log-file is given, since stderr is hidden behind its screen
+func DefaultLogFile() string {
+	return filepath.Join(DefaultConfigDir(), "debug.log")
+}
+
+// openLog returns the logger for --verbose and --log-file, or nil if
+// neither was given
+func (c Config) openLog() (*log.Logger, error) {
+	if c.LogFile == "" {
+		if !c.Verbose {
+			return nil, nil
+		}
+		return log.New(os.Stderr, "", log.LstdFlags|log.Lmicroseconds), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.LogFile), 0o755); err != nil {
+		return nil, fmt.Errorf("cannot open log file: %v", err)
+	}
+	// The file stays open for as long as the program runs
+	file, err := os.OpenFile(c.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open log file: %v", err)
+	}
+	return log.New(file, "", log.LstdFlags|log.Lmicroseconds), nil
+}
+
+// NewClient creates a client for the site configured by the client flags
+func (c Config) NewClient(siteURL string) (*sparktype.Client, error) {
+	opts := []sparktype.ClientOption{
+		sparktype.WithCache(c.CacheDir, c.CacheTTL),
+		sparktype.WithTimeout(c.Timeout),
+		sparktype.WithConcurrency(c.Concurrency),
+		sparktype.WithRetry(c.Retries, c.RetryDelay),
+		sparktype.WithHeaders(c.Headers),
+	}
+	if c.Username != "" {
+		opts = append(opts, sparktype.WithBasicAuth(c.Username, c.Password))
+	}
+	if c.Token != "" {
+		opts = append(opts, sparktype.WithBearerToken(c.Token))
+	}
+	if c.ManifestPath != "" {
+		opts = append(opts, sparktype.WithManifestPath(c.ManifestPath))
+	}
+	if c.ContentPrefix != "" {
+		opts = append(opts, sparktype.WithContentPrefix(c.ContentPrefix))
+	}
+	logger, err := c.openLog()
+	if err != nil {
+		return nil, err
+	}
+	if logger != nil {
+		opts = append(opts, sparktype.WithLogger(logger))
+	}
+
+	client, err := sparktype.NewClient(siteURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" && client.HasBasicAuth() {
+		fmt.Fprintln(os.Stderr, "warning: both a bearer token and basic auth credentials were given; using the bearer token")
+	}
+	return client, nil
+}
+
+// parseHeader parses a "Key: Value" header specification, splitting on the
+// first colon and trimming whitespace around the name and value
+func parseHeader(spec string) (string, string, error) {
+	name, value, found := strings.Cut(spec, ":")
+	if !found {
+		return "", "", fmt.Errorf("header %q is missing a ':' separator, expected \"Key: Value\"", spec)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("header %q has an invalid name, expected \"Key: Value\"", spec)
+	}
+
+	return name, strings.TrimSpace(value), nil
+}
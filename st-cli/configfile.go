@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile returns the path of the file holding default flag values
+func DefaultConfigFile() string {
+	return filepath.Join(DefaultConfigDir(), "config.yaml")
+}
+
+// LoadConfigFile sets flags in fs from the YAML file at path, which maps
+// flag names to values, for example:
+//
+//	theme: dark
+//	timeout: 10s
+//	page-size: 20
+//	wrap: 100
+//	headers:
+//	  X-Api-Key: secret
+//
+// It is applied after the command line is parsed and leaves the flags given
+// there alone, so they override the file. A missing file changes nothing.
+// Settings for flags that fs doesn't have but another command does are
+// ignored; any setting that can't be used keeps its default and is reported
+// in the returned warnings.
+func LoadConfigFile(fs *flag.FlagSet, path string) []string {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("cannot read %s: %v", path, err)}
+	}
+
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return []string{fmt.Sprintf("%s is not valid YAML, using the default settings: %v", path, err)}
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	known := knownFlags()
+	var warnings []string
+	for _, name := range names {
+		flagName := name
+		if name == "headers" {
+			flagName = "header"
+		}
+		if fs.Lookup(flagName) == nil {
+			if !known[flagName] {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown setting %q", path, name))
+			}
+			continue
+		}
+		if given[flagName] {
+			continue
+		}
+
+		values, err := settingValues(name, settings[name])
+		for _, value := range values {
+			if err = fs.Set(flagName, value); err != nil {
+				err = fmt.Errorf("invalid value %q: %v", value, err)
+				break
+			}
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s: %v; using the default (a --%s flag overrides the file)", path, name, err, flagName))
+		}
+	}
+	return warnings
+}
+
+// settingValues converts a setting from the config file to the values to set
+// its flag to. Lists set a repeatable flag once per entry, and the headers
+// mapping sets --header once per header.
+func settingValues(name string, setting interface{}) ([]string, error) {
+	switch v := setting.(type) {
+	case nil:
+		return nil, fmt.Errorf("no value given")
+	case map[string]interface{}:
+		if name != "headers" {
+			return nil, fmt.Errorf("expected a single value, not a mapping")
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = fmt.Sprintf("%s: %v", key, v[key])
+		}
+		return values, nil
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = fmt.Sprint(item)
+		}
+		return values, nil
+	}
+	if name == "headers" {
+		return nil, fmt.Errorf("expected a mapping of header names to values")
+	}
+	return []string{fmt.Sprint(setting)}, nil
+}
+
+// knownFlags returns the names of the flags accepted by any command
+func knownFlags() map[string]bool {
+	known := map[string]bool{}
+	for _, register := range commandFlags {
+		config := DefaultConfig()
+		fs := flag.NewFlagSet("", flag.ContinueOnError)
+		register(fs, &config)
+		fs.VisitAll(func(f *flag.Flag) { known[f.Name] = true })
+	}
+	return known
+}
+
+// parseFlags sets the flags in fs from args and then, for those not given
+// there, from the config file
+func parseFlags(fs *flag.FlagSet, args []string) {
+	fs.Parse(args)
+	for _, warning := range LoadConfigFile(fs, DefaultConfigFile()) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"st-cli/pkg/sparktype"
+)
+
+// nonSlugChars matches runs of characters that don't belong in a file name slug
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a title into a lowercase, hyphenated file name slug
+func slugify(title string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// frontmatter returns the frontmatter to write out for a content file. The
+// standard fields are filled in from the ContentFile when the original
+// metadata doesn't carry them, so they always round-trip.
+func frontmatter(content *sparktype.ContentFile) map[string]interface{} {
+	fields := make(map[string]interface{}, len(content.Metadata)+4)
+	for k, v := range content.Metadata {
+		fields[k] = v
+	}
+
+	if _, ok := fields["title"]; !ok && content.Title != "" {
+		fields["title"] = content.Title
+	}
+	if _, ok := fields["description"]; !ok && content.Description != "" {
+		fields["description"] = content.Description
+	}
+	if _, ok := fields["layout"]; !ok && content.Layout != "" {
+		fields["layout"] = content.Layout
+	}
+	if _, ok := fields["date"]; !ok && !content.Date.IsZero() {
+		fields["date"] = content.Date.Format("2006-01-02")
+	}
+
+	return fields
+}
+
+// markdownDocument reconstructs the source of a content file, with its
+// frontmatter written back out as YAML
+func markdownDocument(content *sparktype.ContentFile) (string, error) {
+	var builder strings.Builder
+
+	fields := frontmatter(content)
+	if len(fields) > 0 {
+		data, err := yaml.Marshal(fields)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode frontmatter: %v", err)
+		}
+		builder.WriteString("---\n")
+		builder.Write(data)
+		builder.WriteString("---\n\n")
+	}
+
+	builder.WriteString(content.Content)
+	if !strings.HasSuffix(content.Content, "\n") {
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
+}
+
+// exportContent writes a content file as markdown into dir, named after the
+// slug (or the title if there's no slug). An existing file is never
+// overwritten; a numeric suffix is added instead. It returns the path written.
+func exportContent(content *sparktype.ContentFile, dir, slug string) (string, error) {
+	document, err := markdownDocument(content)
+	if err != nil {
+		return "", err
+	}
+
+	name := slugify(slug)
+	if name == "" {
+		name = slugify(content.Title)
+	}
+	if name == "" {
+		name = "page"
+	}
+
+	path := filepath.Join(dir, name+".md")
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.md", name, i))
+	}
+
+	if err := os.WriteFile(path, []byte(document), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return path, nil
+}
+
+// exportPath maps a content path from the manifest to a file under dir,
+// mirroring the site's directory layout below its content prefix
+func exportPath(dir, prefix, contentPath string) (string, error) {
+	// Content hosted elsewhere is filed under its path on that host
+	if u, err := url.Parse(contentPath); err == nil && sparktype.IsAbsoluteURL(contentPath) {
+		contentPath = u.Path
+	}
+	rel := strings.TrimPrefix(contentPath, "/")
+	rel = strings.TrimPrefix(rel, strings.TrimPrefix(prefix, "/"))
+	rel = filepath.Clean(filepath.FromSlash(rel))
+	if rel == "." || filepath.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("refusing to export outside %s: %s", dir, contentPath)
+	}
+	if filepath.Ext(rel) != ".md" {
+		rel += ".md"
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// runExport implements `st-cli export`, writing every page and collection
+// item in the site to a mirrored tree of markdown files, reporting progress
+// to out
+func runExport(args []string, out io.Writer) int {
+	config := DefaultConfig()
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: st-cli export [flags] <site-url> <dir>")
+		fs.PrintDefaults()
+	}
+	config.RegisterClientFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	siteURL, dir := fs.Arg(0), fs.Arg(1)
+
+	client, err := config.NewClient(siteURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, err := client.FetchManifest(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	refs := manifest.AllContent()
+	// Running as many workers as the client has request slots keeps every
+	// slot busy
+	workers := client.Concurrency()
+
+	var (
+		mu       sync.Mutex
+		exported int
+		wg       sync.WaitGroup
+	)
+	progress := newProgressLine(out, len(refs))
+	jobs := make(chan sparktype.ContentRef)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range jobs {
+				err := exportRef(client, dir, ref)
+
+				mu.Lock()
+				if err != nil {
+					progress.fail("skipping %s: %v", ref.Path, err)
+				} else {
+					exported++
+					progress.step(ref.Path)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, ref := range refs {
+		jobs <- ref
+	}
+	close(jobs)
+	wg.Wait()
+	progress.finish()
+
+	fmt.Fprintf(out, "Exported %d of %d pages to %s\n", exported, len(refs), dir)
+	if exported < len(refs) {
+		return 1
+	}
+	return 0
+}
+
+// exportRef fetches one piece of content and writes it under dir as the site
+// serves it, so its frontmatter is kept byte for byte
+func exportRef(client *sparktype.Client, dir string, ref sparktype.ContentRef) error {
+	path, err := exportPath(dir, client.ContentPrefix(), ref.Path)
+	if err != nil {
+		return err
+	}
+
+	body, err := client.FetchContentSource(context.Background(), ref.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
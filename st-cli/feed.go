@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"st-cli/pkg/sparktype"
+)
+
+// feedEntry is a collection item with the details a feed needs
+type feedEntry struct {
+	Title       string
+	URL         string
+	Description string
+	Date        time.Time
+}
+
+// rssDocument is an RSS 2.0 feed
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the channel of an RSS feed
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+// rssItem is an entry in an RSS feed
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        *rssGUID `xml:"guid,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Description string   `xml:"description,omitempty"`
+}
+
+// rssGUID identifies an RSS item by its URL
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// atomDocument is an Atom feed
+type atomDocument struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomLink links an Atom feed or entry to its page
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// atomEntry is an entry in an Atom feed
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+// collectionFeed fetches the items of a collection, newest first, leaving
+// out drafts and items that can't be fetched. At most limit items are
+// returned when limit is positive.
+func collectionFeed(client *sparktype.Client, manifest *sparktype.SiteManifest, collectionID string, limit int, warn io.Writer) []feedEntry {
+	var entries []feedEntry
+	for _, item := range manifest.CollectionItems {
+		if item.CollectionID != collectionID {
+			continue
+		}
+		content, err := client.FetchContent(context.Background(), item.Path)
+		if err != nil {
+			fmt.Fprintf(warn, "warning: skipping %s: %v\n", item.Path, err)
+			continue
+		}
+		if content.Draft {
+			continue
+		}
+
+		title := item.Title
+		if content.Title != "" {
+			title = content.Title
+		}
+		link := item.URL
+		if link == "" {
+			link = "/" + item.Slug
+		}
+		entries = append(entries, feedEntry{
+			Title:       title,
+			URL:         client.ResolveURL(link),
+			Description: content.Description,
+			Date:        content.Date,
+		})
+	}
+
+	// Undated items go last, as they do in the browser
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date.IsZero() || entries[j].Date.IsZero() {
+			return !entries[i].Date.IsZero() && entries[j].Date.IsZero()
+		}
+		return entries[i].Date.After(entries[j].Date)
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// rssFeed builds an RSS 2.0 document for a collection
+func rssFeed(title, link, description string, entries []feedEntry) rssDocument {
+	channel := rssChannel{Title: title, Link: link, Description: description}
+	for _, entry := range entries {
+		item := rssItem{
+			Title:       entry.Title,
+			Link:        entry.URL,
+			GUID:        &rssGUID{IsPermaLink: true, Value: entry.URL},
+			Description: entry.Description,
+		}
+		if !entry.Date.IsZero() {
+			item.PubDate = entry.Date.Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	return rssDocument{Version: "2.0", Channel: channel}
+}
+
+// atomFeed builds an Atom document for a collection. Atom requires every
+// entry to have an update time, so undated items use the time of the feed.
+func atomFeed(title, link string, entries []feedEntry, now time.Time) atomDocument {
+	feed := atomDocument{
+		ID:      link,
+		Title:   title,
+		Updated: now.Format(time.RFC3339),
+		Link:    atomLink{Href: link},
+	}
+	if len(entries) > 0 && !entries[0].Date.IsZero() {
+		feed.Updated = entries[0].Date.Format(time.RFC3339)
+	}
+	for _, entry := range entries {
+		updated := now
+		if !entry.Date.IsZero() {
+			updated = entry.Date
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      entry.URL,
+			Title:   entry.Title,
+			Updated: updated.Format(time.RFC3339),
+			Link:    atomLink{Href: entry.URL},
+			Summary: entry.Description,
+		})
+	}
+	return feed
+}
+
+// feedFlags registers the flags of `st-cli feed`
+func feedFlags(fs *flag.FlagSet, config *Config) (format *string, limit *int) {
+	format = fs.String("format", "rss", "feed format: rss or atom")
+	limit = fs.Int("limit", 0, "maximum number of items in the feed (0 for all)")
+	config.RegisterClientFlags(fs)
+	return format, limit
+}
+
+// runFeed implements `st-cli feed`, printing an RSS or Atom feed of a
+// collection's items to out
+func runFeed(args []string, out io.Writer) int {
+	config := DefaultConfig()
+	fs := flag.NewFlagSet("feed", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: st-cli feed [flags] <site-url> <collection-id>")
+		fs.PrintDefaults()
+	}
+	format, limit := feedFlags(fs, &config)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "rss" && *format != "atom" {
+		fmt.Fprintf(os.Stderr, "error: unknown feed format %q (use rss or atom)\n", *format)
+		return 2
+	}
+	siteURL, collectionID := fs.Arg(0), fs.Arg(1)
+
+	client, err := config.NewClient(siteURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, err := client.FetchManifest(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	var collection *sparktype.Collection
+	for i := range manifest.Collections {
+		if manifest.Collections[i].ID == collectionID {
+			collection = &manifest.Collections[i]
+		}
+	}
+	if collection == nil {
+		fmt.Fprintf(os.Stderr, "error: no collection %q in the site\n", collectionID)
+		for _, c := range manifest.Collections {
+			fmt.Fprintf(os.Stderr, "  %s (%s)\n", c.ID, c.Name)
+		}
+		return 1
+	}
+
+	entries := collectionFeed(client, manifest, collectionID, *limit, os.Stderr)
+
+	title := collection.Name
+	if manifest.Title != "" {
+		title = manifest.Title + " - " + collection.Name
+	}
+	link := client.ResolveURL("/")
+
+	var document interface{}
+	if *format == "atom" {
+		document = atomFeed(title, link, entries, time.Now())
+	} else {
+		description := manifest.Description
+		if description == "" {
+			description = title
+		}
+		document = rssFeed(title, link, description, entries)
+	}
+
+	if err := writeFeed(out, document); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeFeed writes an RSS or Atom document to out as indented XML
+func writeFeed(out io.Writer, document interface{}) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
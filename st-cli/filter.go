@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filterMatchStyle marks the characters of a title matched by the filter
+var filterMatchStyle = lipgloss.NewStyle().Underline(true).Bold(true)
+
+// listFilterable reports whether the current view is a list that can be
+// filtered: the menu or a collection listing
+func (a *App) listFilterable() bool {
+	return a.state == StateMainMenu || a.state == StateCollectionListing
+}
+
+// startListFilter opens the filter prompt of the menu or listing. Items are
+// matched fuzzily, so "blg" finds "Blog".
+func (a *App) startListFilter() (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	a.list, cmd = a.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(a.list.KeyMap.Filter.Keys()[0])})
+	return a, cmd
+}
+
+// handleListFilterKey handles keyboard input while the filter prompt has
+// focus. Every key goes to the prompt; enter keeps the filter and esc drops it.
+func (a *App) handleListFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return a.quit()
+	}
+	var cmd tea.Cmd
+	a.list, cmd = a.list.Update(msg)
+	return a, cmd
+}
+
+// clearListFilter drops a filter kept with enter, reporting whether there
+// was one
+func (a *App) clearListFilter() bool {
+	if !a.listFilterable() || a.list.FilterState() == list.Unfiltered {
+		return false
+	}
+	a.list.ResetFilter()
+	return true
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Escape sequences used to highlight matches in the content view
+const (
+	findMatchStyle   = "\x1b[7m"     // Reverse video
+	findCurrentStyle = "\x1b[30;43m" // Black on yellow
+	findResetStyle   = "\x1b[0m"
+)
+
+// findMatch is the position of a match in the content view, in runes of the
+// plain text of a line
+type findMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// startFind opens the find prompt in the content view
+func (a *App) startFind() (tea.Model, tea.Cmd) {
+	a.findInput = textinput.New()
+	a.findInput.Prompt = "/"
+	a.findInput.SetValue(a.findQuery)
+	a.findInput.CursorEnd()
+	a.findInput.Focus()
+	a.findActive = true
+	return a, textinput.Blink
+}
+
+// handleFindKey handles keyboard input while the find prompt is open
+func (a *App) handleFindKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return a.quit()
+
+	case msg.Type == tea.KeyEsc:
+		a.findActive = false
+		a.clearFind()
+		return a, nil
+
+	case msg.Type == tea.KeyEnter:
+		a.findActive = false
+		a.findInput.Blur()
+		a.findQuery = a.findInput.Value()
+		a.runFind()
+		if len(a.findMatches) == 0 {
+			if a.findQuery == "" {
+				return a, nil
+			}
+			return a, a.setStatus(fmt.Sprintf(tr("No matches for %q"), a.findQuery))
+		}
+		return a, nil
+
+	case key.Matches(msg, keys.FindCase):
+		a.findCaseSensitive = !a.findCaseSensitive
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.findInput, cmd = a.findInput.Update(msg)
+	return a, cmd
+}
+
+// runFind finds every match of the query in the content and jumps to the
+// first one at or below the top of the view
+func (a *App) runFind() {
+	a.findMatches = findMatches(a.contentLines, a.findQuery, a.findCaseSensitive)
+	a.findCurrent = 0
+	for i, match := range a.findMatches {
+		if match.line >= a.viewport.YOffset {
+			a.findCurrent = i
+			break
+		}
+	}
+	a.showFindMatches()
+}
+
+// nextFindMatch moves to the next match, or the previous one when delta is
+// negative, wrapping around at either end
+func (a *App) nextFindMatch(delta int) {
+	if len(a.findMatches) == 0 {
+		return
+	}
+	a.findCurrent = (a.findCurrent + delta + len(a.findMatches)) % len(a.findMatches)
+	a.showFindMatches()
+}
+
+// clearFind removes the search and its highlights from the content view
+func (a *App) clearFind() {
+	a.findQuery = ""
+	a.findMatches = nil
+	a.findCurrent = 0
+	a.showFindMatches()
+}
+
+// showFindMatches highlights the matches in the viewport and scrolls the
+// current one into view
+func (a *App) showFindMatches() {
+	offset := a.viewport.YOffset
+
+	// Start again from the unhighlighted lines each time
+	lines := make([]string, len(a.contentLines))
+	copy(lines, a.contentLines)
+
+	byLine := make(map[int][]findMatch)
+	for _, match := range a.findMatches {
+		byLine[match.line] = append(byLine[match.line], match)
+	}
+	var current findMatch
+	if len(a.findMatches) > 0 {
+		current = a.findMatches[a.findCurrent]
+	}
+	for line, matches := range byLine {
+		lines[line] = highlightLine(lines[line], matches, current)
+	}
+
+	a.viewport.SetContent(strings.Join(lines, "\n"))
+	a.viewport.SetYOffset(offset)
+
+	if len(a.findMatches) > 0 {
+		if current.line < a.viewport.YOffset || current.line >= a.viewport.YOffset+a.viewport.Height {
+			// Show the match a few lines from the top, like less does
+			a.viewport.SetYOffset(current.line - a.viewport.Height/4)
+		}
+	}
+}
+
+// findMatches returns the matches of query in the plain text of each line
+func findMatches(lines []string, query string, caseSensitive bool) []findMatch {
+	if query == "" {
+		return nil
+	}
+
+	fold := func(runes []rune) []rune {
+		if caseSensitive {
+			return runes
+		}
+		// Lowercasing rune by rune keeps offsets into the line valid
+		folded := make([]rune, len(runes))
+		for i, r := range runes {
+			folded[i] = unicode.ToLower(r)
+		}
+		return folded
+	}
+
+	queryRunes := fold([]rune(query))
+	var matches []findMatch
+	for i, line := range lines {
+		plain := fold([]rune(ansiSequence.ReplaceAllString(line, "")))
+		for start := 0; start+len(queryRunes) <= len(plain); {
+			pos := runeIndex(plain[start:], queryRunes)
+			if pos < 0 {
+				break
+			}
+			matches = append(matches, findMatch{
+				line:  i,
+				start: start + pos,
+				end:   start + pos + len(queryRunes),
+			})
+			start += pos + len(queryRunes)
+		}
+	}
+	return matches
+}
+
+// highlightLine wraps the matches in a styled line in highlight escape
+// sequences, keeping the line's own styling outside them
+func highlightLine(line string, matches []findMatch, current findMatch) string {
+	var builder strings.Builder
+	pos := 0        // Position in the plain text
+	active := ""    // Highlight currently applied
+	lineStyle := "" // Last escape sequence from the line itself
+
+	styleAt := func(pos int) string {
+		for _, match := range matches {
+			if pos >= match.start && pos < match.end {
+				if match == current {
+					return findCurrentStyle
+				}
+				return findMatchStyle
+			}
+		}
+		return ""
+	}
+
+	for i := 0; i < len(line); {
+		// Copy escape sequences through, restoring the highlight after them
+		if line[i] == '\x1b' {
+			if loc := ansiSequence.FindStringIndex(line[i:]); loc != nil && loc[0] == 0 {
+				lineStyle = line[i : i+loc[1]]
+				builder.WriteString(lineStyle)
+				builder.WriteString(active)
+				i += loc[1]
+				continue
+			}
+		}
+
+		if style := styleAt(pos); style != active {
+			if active != "" {
+				builder.WriteString(findResetStyle)
+				builder.WriteString(lineStyle)
+			}
+			builder.WriteString(style)
+			active = style
+		}
+
+		r, size := utf8.DecodeRuneInString(line[i:])
+		builder.WriteRune(r)
+		i += size
+		pos++
+	}
+	if active != "" {
+		builder.WriteString(findResetStyle)
+	}
+	return builder.String()
+}
+
+// findStatus describes the current search for the help line
+func (a *App) findStatus() string {
+	if a.findQuery == "" {
+		return ""
+	}
+	if len(a.findMatches) == 0 {
+		return fmt.Sprintf(tr("no matches for %q"), a.findQuery)
+	}
+	return fmt.Sprintf(tr("%q: match %d of %d • n/N: next/prev • esc: clear"), a.findQuery, a.findCurrent+1, len(a.findMatches))
+}
+
+// findPrompt renders the find input with its options
+func (a *App) findPrompt() string {
+	mode := tr("ignoring case")
+	if a.findCaseSensitive {
+		mode = tr("case sensitive")
+	}
+	return fmt.Sprintf("%s  %s", a.findInput.View(), helpStyle.Render(fmt.Sprintf(tr("enter: find • tab: %s • esc: cancel"), mode)))
+}
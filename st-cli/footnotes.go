@@ -0,0 +1,71 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"st-cli/pkg/sparktype"
+)
+
+// jumpFootnote scrolls from the first footnote reference in view to its
+// note at the end of the page. Pressed again before scrolling, it returns to
+// where the reference was read; with a note in view and nothing to return
+// to, it goes to the note's first reference.
+func (a *App) jumpFootnote() (tea.Model, tea.Cmd) {
+	if a.showRaw {
+		return a, nil
+	}
+	if a.footnoteBack >= 0 && a.viewport.YOffset == a.footnoteAt {
+		a.viewport.SetYOffset(a.footnoteBack)
+		a.footnoteBack = -1
+		return a, nil
+	}
+
+	plain := make([]string, len(a.contentLines))
+	notes := map[int]int{}
+	firstNote := len(a.contentLines)
+	for i, line := range a.contentLines {
+		plain[i] = ansiSequence.ReplaceAllString(line, "")
+		if number, ok := sparktype.NoteNumber(plain[i]); ok {
+			notes[number] = i
+			if i < firstNote {
+				firstNote = i
+			}
+		}
+	}
+	if len(notes) == 0 {
+		return a, a.setStatus(tr("This page has no footnotes"))
+	}
+
+	top := a.viewport.YOffset
+	bottom := top + a.viewport.Height
+	if bottom > len(plain) {
+		bottom = len(plain)
+	}
+	for i := top; i < bottom && i < firstNote; i++ {
+		for _, number := range sparktype.FootnoteMarkers(plain[i]) {
+			if line, ok := notes[number]; ok {
+				a.viewport.SetYOffset(line)
+				a.footnoteBack, a.footnoteAt = top, a.viewport.YOffset
+				return a, nil
+			}
+		}
+	}
+
+	// No references in view, so go from a note in view to its reference
+	for i := max(top, firstNote); i < bottom; i++ {
+		number, ok := sparktype.NoteNumber(plain[i])
+		if !ok {
+			continue
+		}
+		for line := 0; line < firstNote; line++ {
+			for _, marker := range sparktype.FootnoteMarkers(plain[line]) {
+				if marker == number {
+					a.viewport.SetYOffset(line)
+					a.footnoteBack = -1
+					return a, nil
+				}
+			}
+		}
+	}
+	return a, a.setStatus(tr("No footnote references in view"))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// undatedGroup is the heading for items without a date
+const undatedGroup = "Undated"
+
+// yearHeaderStyle styles the year headings in a grouped listing
+var yearHeaderStyle = lipgloss.NewStyle().
+	Foreground(accentColor).
+	Bold(true).
+	PaddingLeft(2)
+
+// YearHeader is a heading row in a listing grouped by year. It can't be
+// selected.
+type YearHeader struct {
+	Year string
+}
+
+// FilterValue returns the value to filter on
+func (y YearHeader) FilterValue() string {
+	return y.Year
+}
+
+// yearGroupDelegate draws year headings in place of the default item
+// rendering
+type yearGroupDelegate struct {
+	list.DefaultDelegate
+}
+
+// Render renders a heading or a collection item
+func (d yearGroupDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if header, ok := item.(YearHeader); ok {
+		fmt.Fprint(w, yearHeaderStyle.Render("── "+header.Year+" ──"))
+		return
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// itemGroup returns the heading an item is listed under
+func itemGroup(item sparktype.CollectionItem) string {
+	if item.Date.IsZero() {
+		return undatedGroup
+	}
+	return strconv.Itoa(item.Date.Year())
+}
+
+// sortByYearGroup orders items by year, keeping their order within each
+// year. Years follow the direction of the date sort, newest first unless
+// sorting oldest first, and undated items come last.
+func (a *App) sortByYearGroup(items []sparktype.CollectionItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		first, second := items[i].Date, items[j].Date
+		if first.IsZero() || second.IsZero() {
+			return !first.IsZero() && second.IsZero()
+		}
+		if a.collectionSort == sortOldest {
+			return first.Year() < second.Year()
+		}
+		return first.Year() > second.Year()
+	})
+}
+
+// withYearHeaders inserts a heading before the first item of each year
+func withYearHeaders(items []CollectionItemWrapper) []list.Item {
+	var grouped []list.Item
+	group := ""
+	for i, item := range items {
+		if g := itemGroup(item.CollectionItem); i == 0 || g != group {
+			grouped = append(grouped, YearHeader{Year: g})
+			group = g
+		}
+		grouped = append(grouped, item)
+	}
+	return grouped
+}
+
+// toggleYearGroups turns grouping the listing by year on or off
+func (a *App) toggleYearGroups() (tea.Model, tea.Cmd) {
+	a.groupByYear = !a.groupByYear
+	a.sortCollectionItems(a.collectionAll)
+	a.applyCollectionFilter()
+	a.setupCollectionListingUI()
+	return a, nil
+}
+
+// skipYearHeader moves the listing's selection off a heading, in the
+// direction it was moving
+func (a *App) skipYearHeader(up bool) {
+	if _, ok := a.list.SelectedItem().(YearHeader); !ok {
+		return
+	}
+	if up && a.list.Index() > 0 {
+		a.list.CursorUp()
+	} else if a.list.Index() < len(a.list.Items())-1 {
+		a.list.CursorDown()
+	} else {
+		a.list.CursorUp()
+	}
+}
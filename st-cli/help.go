@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpGroupStyle styles the heading of each group in the help overlay
+var helpGroupStyle = lipgloss.NewStyle().
+	Foreground(accentColor).
+	Bold(true)
+
+// helpGroup is a set of key bindings that apply in one context
+type helpGroup struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// helpGroups returns every key binding, grouped by where it applies
+func (k KeyMap) helpGroups() []helpGroup {
+	return []helpGroup{
+		{"Everywhere", []key.Binding{k.Up, k.Down, k.Enter, k.Back, k.Refresh, k.Bookmarks, k.Info, k.Dismiss, k.Palette, k.Help, k.Quit}},
+		{"Main menu", []key.Binding{k.Filter, k.Search, k.Tree, k.Expand, k.Collapse, k.NextColumn, k.PrevColumn, k.Forward, k.Bookmark, k.Recent}},
+		{"Collection listing", []key.Binding{k.Filter, k.NextColumn, k.PrevColumn, k.NextPage, k.PrevPage, k.GoToPage, k.ShowAll, k.Sort, k.Group, k.JumpLetter, k.Tags, k.Open, k.Bookmark}},
+		{"Content view", []key.Binding{k.PageDown, k.PageUp, k.HalfDown, k.HalfUp, k.Top, k.Bottom, k.NextArticle, k.PrevArticle, k.Find, k.FindNext, k.FindPrev, k.TOC, k.Links, k.Raw, k.Metadata, k.Footnote, k.Open, k.CopyURL, k.Export, k.Bookmark}},
+		{"Find in page", []key.Binding{k.FindCase}},
+		{"Bookmarks", []key.Binding{k.Bookmark}},
+	}
+}
+
+// showHelpOverlay opens the full list of key bindings over the current view
+func (a *App) showHelpOverlay() (tea.Model, tea.Cmd) {
+	a.helpViewport = viewport.New(a.width, a.height-2)
+	a.helpViewport.SetContent(renderHelp(keys.helpGroups()))
+	a.showHelp = true
+	return a, nil
+}
+
+// handleHelpKey handles keyboard input while the help overlay is open
+func (a *App) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return a.quit()
+	case key.Matches(msg, keys.Help), msg.Type == tea.KeyEsc:
+		a.showHelp = false
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.helpViewport, cmd = a.helpViewport.Update(msg)
+	return a, cmd
+}
+
+// renderHelp lays out the key bindings in groups, with their keys aligned
+func renderHelp(groups []helpGroup) string {
+	width := 0
+	for _, group := range groups {
+		for _, binding := range group.Bindings {
+			if w := lipgloss.Width(binding.Help().Key); w > width {
+				width = w
+			}
+		}
+	}
+
+	var builder strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(helpGroupStyle.Render(tr(group.Title)))
+		builder.WriteString("\n")
+		for _, binding := range group.Bindings {
+			help := binding.Help()
+			padding := strings.Repeat(" ", width-lipgloss.Width(help.Key))
+			builder.WriteString(fmt.Sprintf("  %s%s  %s\n", help.Key, padding, tr(help.Desc)))
+		}
+	}
+	return builder.String()
+}
+
+// helpView renders the help overlay
+func (a *App) helpView() string {
+	help := helpStyle.Render(tr("↑/↓: scroll • ?/esc: close"))
+	return fmt.Sprintf("%s\n%s\n%s", titleStyle.Render(tr("Keys")), a.helpViewport.View(), help)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// maxRecent is the number of recently viewed pages kept for each site
+const maxRecent = 20
+
+// RecentPage is a page the user has viewed
+type RecentPage struct {
+	Title  string    `json:"title"`
+	Path   string    `json:"path"`
+	Viewed time.Time `json:"viewed"`
+}
+
+// RecentItem wraps a recently viewed page for the list component
+type RecentItem struct {
+	Page RecentPage
+}
+
+// Title returns the title of the page
+func (r RecentItem) Title() string {
+	return r.Page.Title
+}
+
+// Description returns when the page was last viewed
+func (r RecentItem) Description() string {
+	return fmt.Sprintf(tr("Viewed %s"), formatDate(r.Page.Viewed.Local(), "2 Jan 2006 15:04"))
+}
+
+// FilterValue returns the value to filter on
+func (r RecentItem) FilterValue() string {
+	return r.Page.Title
+}
+
+// historyStore holds the recently viewed pages for every site, keyed by site
+// URL, most recent first
+type historyStore struct {
+	path  string
+	sites map[string][]RecentPage
+}
+
+// loadHistory reads the history file at path. A missing or unreadable file
+// gives an empty history, which replaces the file when next saved.
+func loadHistory(path string) *historyStore {
+	store := &historyStore{path: path, sites: make(map[string][]RecentPage)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return store
+	}
+	if err := json.Unmarshal(data, &store.sites); err != nil || store.sites == nil {
+		store.sites = make(map[string][]RecentPage)
+	}
+	return store
+}
+
+// list returns the recently viewed pages for a site, most recent first
+func (h *historyStore) list(site string) []RecentPage {
+	return h.sites[site]
+}
+
+// record moves a page to the front of a site's history, dropping any earlier
+// visit and the oldest pages beyond maxRecent, and saves the file
+func (h *historyStore) record(site string, page RecentPage) error {
+	pages := []RecentPage{page}
+	for _, existing := range h.sites[site] {
+		if existing.Path != page.Path && len(pages) < maxRecent {
+			pages = append(pages, existing)
+		}
+	}
+	h.sites[site] = pages
+	return h.save()
+}
+
+// save writes the history file
+func (h *historyStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h.sites, "", "  ")
+	if err != nil {
+		return err
+	}
+	return sparktype.WriteFileAtomic(h.path, data)
+}
+
+// recordVisit adds the page being viewed to the history
+func (a *App) recordVisit() {
+	if a.content == nil || a.currentPath == "" {
+		return
+	}
+	title := a.content.Title
+	if title == "" {
+		title = a.currentPath
+	}
+
+	// History is a convenience, so failing to save it doesn't interrupt
+	// reading; the next visit tries again
+	a.history.record(a.client.GetBaseURL(), RecentPage{
+		Title:  title,
+		Path:   a.currentPath,
+		Viewed: time.Now(),
+	})
+}
+
+// showRecent opens the list of recently viewed pages for the site
+func (a *App) showRecent() (tea.Model, tea.Cmd) {
+	pages := a.history.list(a.client.GetBaseURL())
+	if len(pages) == 0 {
+		return a, a.setStatus(tr("No recently viewed pages"))
+	}
+
+	items := make([]list.Item, len(pages))
+	for i, page := range pages {
+		items[i] = RecentItem{Page: page}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.recentList = list.New(items, delegate, a.width, a.height-4)
+	a.recentList.Title = tr("Recently viewed")
+	a.recentList.SetShowStatusBar(false)
+	a.recentList.SetShowHelp(false)
+
+	a.state = StateRecent
+	return a, nil
+}
+
+// openRecent loads the selected recently viewed page
+func (a *App) openRecent() (tea.Model, tea.Cmd) {
+	item, ok := a.recentList.SelectedItem().(RecentItem)
+	if !ok {
+		return a, nil
+	}
+	a.beginLoading()
+	return a, a.loadContent(item.Page.Path)
+}
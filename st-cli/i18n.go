@@ -0,0 +1,326 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"st-cli/pkg/sparktype"
+)
+
+// defaultLocale is the language the interface is written in, used when no
+// translation is chosen or available
+const defaultLocale = "en"
+
+// catalogs holds the translations of the interface, keyed by locale and
+// then by the English message. Messages without a translation are shown in
+// English, so a catalog can be partial.
+var catalogs = map[string]map[string]string{
+	"fr": catalogFrench,
+}
+
+// locale is the language the interface is shown in
+var locale = defaultLocale
+
+// Locales returns the locales the interface can be shown in
+func Locales() []string {
+	locales := []string{defaultLocale}
+	for name := range catalogs {
+		locales = append(locales, name)
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// setLocale chooses the language of the interface: lang if given, otherwise
+// the one named by the environment. Languages without a catalog fall back to
+// English.
+func setLocale(lang string) {
+	if lang == "" {
+		lang = localeFromEnv()
+	}
+	locale = defaultLocale
+	if _, ok := catalogs[lang]; ok {
+		locale = lang
+	}
+}
+
+// localeFromEnv returns the language named by the environment, following
+// the precedence of LC_ALL, LC_MESSAGES and LANG, as in "fr" for
+// "fr_FR.UTF-8"
+func localeFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if i := strings.IndexAny(value, "_.@"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.ToLower(value)
+	}
+	return defaultLocale
+}
+
+// tr returns the translation of an interface message into the current
+// locale, or the message itself if there isn't one. Messages with verbs are
+// translated before they are formatted.
+func tr(message string) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// formatDate formats t like time.Format, with the layout and month names
+// translated into the current locale
+func formatDate(t time.Time, layout string) string {
+	return sparktype.FormatDate(t, layout, tr)
+}
+
+// catalogFrench translates the interface into French
+var catalogFrench = map[string]string{
+	// Dates
+	"January 2, 2006": "2 January 2006",
+	"January":         "janvier",
+	"February":        "février",
+	"March":           "mars",
+	"April":           "avril",
+	"May":             "mai",
+	"June":            "juin",
+	"July":            "juillet",
+	"August":          "août",
+	"September":       "septembre",
+	"October":         "octobre",
+	"November":        "novembre",
+	"December":        "décembre",
+	"Jan":             "janv.",
+	"Feb":             "févr.",
+	"Mar":             "mars",
+	"Apr":             "avr.",
+	"Jun":             "juin",
+	"Jul":             "juil.",
+	"Aug":             "août",
+	"Sep":             "sept.",
+	"Oct":             "oct.",
+	"Nov":             "nov.",
+	"Dec":             "déc.",
+
+	// Pages
+	"By %s":                    "Par %s",
+	"Published: %s":            "Publié le %s",
+	"%d min read · 1 word":     "%d min de lecture · 1 mot",
+	"%d min read · %d words":   "%d min de lecture · %d mots",
+	"1 word":                   "1 mot",
+	"%d words":                 "%d mots",
+	"Date unavailable":         "Date indisponible",
+	"Viewed %s":                "Consulté le %s",
+	"Home":                     "Accueil",
+	"1 item":                   "1 élément",
+	"%d items":                 "%d éléments",
+	"All items":                "Tous les éléments",
+	"Page %d of %d":            "Page %d sur %d",
+	"Tag: #%s (%d of %d)":      "Étiquette : #%s (%d sur %d)",
+	"newest first":             "récents d'abord",
+	"oldest first":             "anciens d'abord",
+	"by title":                 "par titre",
+	"A–Z: jump to letter":      "A–Z : aller à la lettre",
+	"f: forward":               "f: suivant",
+	"rendered":                 "rendu",
+	"raw":                      "brut",
+	"%d of %d fetched":         "%d sur %d récupérés",
+	"%d results in %d pages":   "%d résultats dans %d pages",
+	"Search: ":                 "Recherche : ",
+	"words to find":            "mots à chercher",
+	"Title match":              "Correspond au titre",
+	"1 command":                "1 commande",
+	"%d commands":              "%d commandes",
+	"no matches for %q":        "aucun résultat pour %q",
+	"ignoring case":            "sans casse",
+	"case sensitive":           "avec casse",
+	"Indexing site for search": "Indexation du site pour la recherche",
+	"[draft]":                  "[brouillon]",
+	"Bookmarks":                "Favoris",
+	"Recently viewed":          "Consultés récemment",
+	"Links":                    "Liens",
+	"Contents":                 "Sommaire",
+	"Filter %s by tag":         "Filtrer %s par étiquette",
+	"Go to page: ":             "Aller à la page : ",
+	"Item %s… (type another digit, or wait to select)": "Élément %s… (tapez un autre chiffre, ou attendez pour choisir)",
+	"command": "commande",
+	"key: ":   "touche : ",
+
+	// Site info
+	"Site info":    "Infos du site",
+	"Site":         "Site",
+	"URL":          "URL",
+	"Title":        "Titre",
+	"Site ID":      "ID du site",
+	"Generator":    "Générateur",
+	"Theme":        "Thème",
+	"Theme config": "Configuration du thème",
+	"(none)":       "(aucun)",
+	" (st-cli supports %s and later, before %s)": " (st-cli prend en charge %s et suivantes, avant %s)",
+	"cannot show the theme config: %v":           "impossible d'afficher la configuration du thème : %v",
+
+	// Loading and errors
+	"Loading site":            "Chargement du site",
+	"Loading %s":              "Chargement de %s",
+	"Error: %v":               "Erreur : %v",
+	"Not a SparkType site":    "Ce n'est pas un site SparkType",
+	"%s has a manifest, but:": "%s a un manifeste, mais :",
+	"Check that the URL points at the root of a SparkType site.": "Vérifiez que l'URL désigne la racine d'un site SparkType.",
+	"Page not found": "Page introuvable",
+	"x: dismiss":     "x: masquer",
+	"Offline — showing cached copy from %s":                                                                       "Hors ligne — copie en cache du %s",
+	"The site's generator version %q isn't one st-cli recognises; some pages may not display as intended":         "La version du générateur du site, %q, n'est pas reconnue par st-cli ; certaines pages peuvent mal s'afficher",
+	"The site was built by generator %s, older than st-cli supports (%s); some pages may not display as intended": "Le site a été généré par la version %s, antérieure à celles que st-cli prend en charge (%s) ; certaines pages peuvent mal s'afficher",
+	"The site was built by generator %s, newer than st-cli supports (%s); some pages may not display as intended": "Le site a été généré par la version %s, plus récente que celles que st-cli prend en charge (%s) ; certaines pages peuvent mal s'afficher",
+	"%s and later, before %s":      "%s et suivantes, avant %s",
+	"Frontmatter":                  "Métadonnées",
+	"This page has no frontmatter": "Cette page n'a pas de métadonnées",
+	"Embedded media":               "Média intégré",
+	"The site needs credentials; give them with --user or --token.":        "Le site demande des identifiants ; indiquez-les avec --user ou --token.",
+	"Check the URL, or give the manifest's location with --manifest-path.": "Vérifiez l'URL, ou indiquez l'emplacement du manifeste avec --manifest-path.",
+	"The site couldn't be reached; check your connection and the URL.":     "Le site est injoignable ; vérifiez votre connexion et l'URL.",
+	"The site sent a file that couldn't be read.":                          "Le site a envoyé un fichier illisible.",
+	"%s isn't on the site. It may have been moved or removed.":             "%s n'est pas sur le site. Elle a peut-être été déplacée ou supprimée.",
+
+	// Status messages
+	"Export failed: %v":                              "Échec de l'export : %v",
+	"Saved to %s":                                    "Enregistré dans %s",
+	"No URL available for this page":                 "Aucune URL pour cette page",
+	"Could not open browser: %v":                     "Impossible d'ouvrir le navigateur : %v",
+	"Opened %s":                                      "Ouvert : %s",
+	"Could not copy the URL: %v":                     "Impossible de copier l'URL : %v",
+	"Copied %s":                                      "Copié : %s",
+	"Updated from the site":                          "Mis à jour depuis le site",
+	"Could not save bookmarks: %v":                   "Impossible d'enregistrer les favoris : %v",
+	"Bookmarked %s":                                  "Ajouté aux favoris : %s",
+	"Removed bookmark for %s":                        "Retiré des favoris : %s",
+	"No bookmarks yet; press b on a page to add one": "Aucun favori ; appuyez sur b sur une page pour en ajouter un",
+	"No matches for %q":                              "Aucun résultat pour %q",
+	"This page has no footnotes":                     "Cette page n'a pas de notes",
+	"No footnote references in view":                 "Aucun appel de note visible",
+	"No recently viewed pages":                       "Aucune page consultée récemment",
+	"This page has no links":                         "Cette page n'a pas de liens",
+	"This page has no headings":                      "Cette page n'a pas de titres",
+	"Couldn't find that heading on the page":         "Titre introuvable dans la page",
+	"Sorted %s":                                      "Trié : %s",
+	"No titles start with %s":                        "Aucun titre ne commence par %s",
+	"There is only one page":                         "Il n'y a qu'une page",
+	"No page %q; pages run from 1 to %d":             "Pas de page %q ; les pages vont de 1 à %d",
+	"Not watching for changes: %v":                   "Modifications non surveillées : %v",
+
+	// Key help
+	"Keys":                     "Touches",
+	"Everywhere":               "Partout",
+	"Main menu":                "Menu principal",
+	"Collection listing":       "Liste de la collection",
+	"Content view":             "Page",
+	"Find in page":             "Recherche dans la page",
+	"up":                       "haut",
+	"down":                     "bas",
+	"select":                   "choisir",
+	"back":                     "retour",
+	"quit":                     "quitter",
+	"refresh":                  "actualiser",
+	"next page":                "page suivante",
+	"prev page":                "page précédente",
+	"search":                   "rechercher",
+	"filter by tag":            "filtrer par étiquette",
+	"toggle raw markdown":      "afficher la source markdown",
+	"open in browser":          "ouvrir dans le navigateur",
+	"export to markdown":       "exporter en markdown",
+	"contents":                 "sommaire",
+	"find in page":             "chercher dans la page",
+	"next match":               "résultat suivant",
+	"previous match":           "résultat précédent",
+	"toggle case sensitivity":  "respecter la casse ou non",
+	"toggle tree view":         "arborescence",
+	"expand":                   "déplier",
+	"collapse":                 "replier",
+	"toggle bookmark":          "ajouter ou retirer des favoris",
+	"bookmarks":                "favoris",
+	"recently viewed":          "consultés récemment",
+	"forward":                  "suivant",
+	"all keys":                 "toutes les touches",
+	"toggle pages / all items": "par pages / tous les éléments",
+	"go to page":               "aller à la page",
+	"change sort order":        "changer l'ordre de tri",
+	"group by year":            "grouper par année",
+	"jump to letter (sorted by title, where B no longer opens bookmarks)": "aller à la lettre (tri par titre, où B n'ouvre plus les favoris)",
+	"next item in collection":        "élément suivant de la collection",
+	"previous item in collection":    "élément précédent de la collection",
+	"links on the page":              "liens de la page",
+	"toggle frontmatter":             "afficher les métadonnées",
+	"footnote / back":                "note / retour",
+	"copy URL":                       "copier l'URL",
+	"dismiss warning":                "masquer l'avertissement",
+	"top of page":                    "haut de la page",
+	"bottom of page":                 "bas de la page",
+	"half page down":                 "demi-page suivante",
+	"half page up":                   "demi-page précédente",
+	"page down":                      "page suivante",
+	"page up":                        "page précédente",
+	"command palette":                "palette de commandes",
+	"site info":                      "infos du site",
+	"filter the list":                "filtrer la liste",
+	"next column (wide windows)":     "colonne suivante (fenêtres larges)",
+	"previous column (wide windows)": "colonne précédente (fenêtres larges)",
+
+	// Commands
+	"Refresh":                     "Actualiser",
+	"Search the site":             "Rechercher dans le site",
+	"Filter the list":             "Filtrer la liste",
+	"Open in browser":             "Ouvrir dans le navigateur",
+	"Copy page URL":               "Copier l'URL de la page",
+	"Export page":                 "Exporter la page",
+	"Toggle raw markdown":         "Afficher la source markdown",
+	"Toggle frontmatter":          "Afficher les métadonnées",
+	"Jump to footnote":            "Aller à la note",
+	"Table of contents":           "Sommaire",
+	"Links on the page":           "Liens de la page",
+	"Next item in collection":     "Élément suivant de la collection",
+	"Previous item in collection": "Élément précédent de la collection",
+	"Go to page":                  "Aller à la page",
+	"Toggle show all items":       "Afficher tous les éléments ou par pages",
+	"Change sort order":           "Changer l'ordre de tri",
+	"Group by year":               "Grouper par année",
+	"Filter by tag":               "Filtrer par étiquette",
+	"Toggle site tree":            "Arborescence du site",
+	"Bookmark this page":          "Ajouter la page aux favoris",
+	"Show bookmarks":              "Afficher les favoris",
+	"Show keys":                   "Afficher les touches",
+	"Quit":                        "Quitter",
+
+	// Help lines
+	"r: retry":    "r: réessayer",
+	"esc: back":   "esc: retour",
+	"esc: cancel": "esc: annuler",
+	"q: quit":     "q: quitter",
+	"↑/↓: navigate • 0-9: select by number • enter: select • /: filter • s: search • v: tree view • b/B: bookmark/bookmarks • R: recent • ?: all keys • q: quit • r: refresh": "↑/↓: naviguer • 0-9: choisir par numéro • enter: choisir • /: filtrer • s: rechercher • v: arborescence • b/B: favori/favoris • R: récents • ?: toutes les touches • q: quitter • r: actualiser",
+	"↑/↓: navigate • →/←: expand/collapse • enter: open • v: flat menu • /: filter • s: search • q: quit":                                                                     "↑/↓: naviguer • →/←: déplier/replier • enter: ouvrir • v: menu simple • /: filtrer • s: rechercher • q: quitter",
+	"↑/↓: navigate • 0-9: select by number • enter: select • /: filter • esc: up a level • f: forward • q: quit":                                                              "↑/↓: naviguer • 0-9: choisir par numéro • enter: choisir • /: filtrer • esc: niveau supérieur • f: suivant • q: quitter",
+	"a: show all":   "a: tout afficher",
+	"a: show pages": "a: afficher par pages",
+	"←/→: prev/next page • g: go to page • a: show all": "←/→: page préc./suiv. • g: aller à la page • a: tout afficher",
+	"↑/↓: navigate • 0-9: select by number • /: filter • %s • s: sort (%s) • y: group by year • t: filter by tag • o: open in browser • b: bookmark • esc: back • q: quit": "↑/↓: naviguer • 0-9: choisir par numéro • /: filtrer • %s • s: trier (%s) • y: grouper par année • t: filtrer par étiquette • o: ouvrir dans le navigateur • b: favori • esc: retour • q: quitter",
+	"↑/↓: navigate • enter: apply filter • esc: cancel":                              "↑/↓: naviguer • enter: appliquer le filtre • esc: annuler",
+	"↑/↓: navigate • enter: jump to heading • esc: back to page":                     "↑/↓: naviguer • enter: aller au titre • esc: retour à la page",
+	"↑/↓: navigate • 0-9: follow by number • enter: follow link • esc: back to page": "↑/↓: naviguer • 0-9: suivre par numéro • enter: suivre le lien • esc: retour à la page",
+	"↑/↓: navigate • enter: open • b: remove bookmark • esc: back":                   "↑/↓: naviguer • enter: ouvrir • b: retirer le favori • esc: retour",
+	"↑/↓: navigate • enter: open • esc: back":                                        "↑/↓: naviguer • enter: ouvrir • esc: retour",
+	"↑/↓: scroll%s • /: find • c: contents • L: links • t: toggle raw (%s) • m: frontmatter • o: open in browser • e: export • b: bookmark • esc: back • q: quit": "↑/↓: défiler%s • /: chercher • c: sommaire • L: liens • t: source (%s) • m: métadonnées • o: ouvrir dans le navigateur • e: exporter • b: favori • esc: retour • q: quitter",
+	" • [/]: prev/next item": " • [/]: élément préc./suiv.",
+	" • ]: next item":        " • ]: élément suivant",
+	" • [: prev item":        " • [: élément précédent",
+	"%q: match %d of %d • n/N: next/prev • esc: clear":              "%q : résultat %d sur %d • n/N: suivant/précédent • esc: effacer",
+	"enter: find • tab: %s • esc: cancel":                           "enter: chercher • tab: %s • esc: annuler",
+	"enter: go • esc: cancel":                                       "enter: aller • esc: annuler",
+	"↑/↓: scroll • ?/esc: close":                                    "↑/↓: défiler • ?/esc: fermer",
+	"↑/↓: scroll • i/esc: close":                                    "↑/↓: défiler • i/esc: fermer",
+	"type to filter • ↑/↓: navigate • enter: run • esc: close • %s": "tapez pour filtrer • ↑/↓: naviguer • enter: exécuter • esc: fermer • %s",
+	"type to search • ↑/↓: navigate • enter: open • esc: back":      "tapez pour rechercher • ↑/↓: naviguer • enter: ouvrir • esc: retour",
+}
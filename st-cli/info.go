@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"st-cli/pkg/sparktype"
+)
+
+// showInfoOverlay opens the details of the site and its theme over the
+// current view
+func (a *App) showInfoOverlay() (tea.Model, tea.Cmd) {
+	a.infoViewport = viewport.New(a.width, a.height-2)
+	a.infoViewport.SetContent(renderSiteInfo(a.client.GetBaseURL(), a.manifest))
+	a.showInfo = true
+	return a, nil
+}
+
+// handleInfoKey handles keyboard input while the info overlay is open
+func (a *App) handleInfoKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return a.quit()
+	case key.Matches(msg, keys.Info), msg.Type == tea.KeyEsc:
+		a.showInfo = false
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.infoViewport, cmd = a.infoViewport.Update(msg)
+	return a, cmd
+}
+
+// renderSiteInfo lists the site's details, followed by its theme
+// configuration as YAML with the keys sorted
+func renderSiteInfo(siteURL string, manifest *sparktype.SiteManifest) string {
+	orNone := func(value string) string {
+		if value == "" {
+			return tr("(none)")
+		}
+		return value
+	}
+
+	var builder strings.Builder
+	builder.WriteString(helpGroupStyle.Render(tr("Site")))
+	builder.WriteString("\n")
+	for _, field := range [][2]string{
+		{tr("URL"), siteURL},
+		{tr("Title"), orNone(manifest.Title)},
+		{tr("Site ID"), orNone(manifest.SiteID)},
+		{tr("Generator"), orNone(manifest.GeneratorVersion) + fmt.Sprintf(tr(" (st-cli supports %s and later, before %s)"), MinGeneratorVersion, MaxGeneratorVersion)},
+		{tr("Theme"), orNone(manifest.Theme.Name)},
+	} {
+		builder.WriteString(fmt.Sprintf("  %-10s %s\n", field[0], field[1]))
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(helpGroupStyle.Render(tr("Theme config")))
+	builder.WriteString("\n")
+	if len(manifest.Theme.Config) == 0 {
+		builder.WriteString("  " + tr("(none)") + "\n")
+		return builder.String()
+	}
+
+	// yaml.v3 writes map keys in sorted order, so the dump is stable
+	var dump strings.Builder
+	encoder := yaml.NewEncoder(&dump)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest.Theme.Config); err != nil {
+		builder.WriteString("  " + fmt.Sprintf(tr("cannot show the theme config: %v"), err) + "\n")
+		return builder.String()
+	}
+	encoder.Close()
+	for _, line := range strings.Split(strings.TrimRight(dump.String(), "\n"), "\n") {
+		builder.WriteString("  " + line + "\n")
+	}
+	return builder.String()
+}
+
+// infoView renders the info overlay
+func (a *App) infoView() string {
+	help := helpStyle.Render(tr("↑/↓: scroll • i/esc: close"))
+	return fmt.Sprintf("%s\n%s\n%s", titleStyle.Render(tr("Site info")), a.infoViewport.View(), help)
+}
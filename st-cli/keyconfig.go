@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"gopkg.in/yaml.v3"
+)
+
+// namedKeys are the multi-character key names accepted in keys.yaml, as
+// Bubble Tea reports them
+var namedKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"enter": true, "esc": true, "tab": true, "shift+tab": true,
+	"backspace": true, "delete": true, "insert": true, "home": true, "end": true,
+	"pgup": true, "pgdown": true,
+}
+
+// keyList is the keys configured for one binding: either a single key or a
+// list of them
+type keyList []string
+
+// UnmarshalYAML accepts a single key as well as a list
+func (k *keyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = keyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// configurableBindings returns the bindings that keys.yaml may override,
+// keyed by their name in the file
+func (k *KeyMap) configurableBindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":        &k.Up,
+		"down":      &k.Down,
+		"enter":     &k.Enter,
+		"back":      &k.Back,
+		"quit":      &k.Quit,
+		"refresh":   &k.Refresh,
+		"next_page": &k.NextPage,
+		"prev_page": &k.PrevPage,
+	}
+}
+
+// LoadKeyBindings overrides bindings in the key map with those configured in
+// the YAML file at path, for example:
+//
+//	up: [k, up]
+//	back: esc
+//
+// A missing file leaves the defaults in place. Any binding that can't be
+// used keeps its default and is reported in the returned warnings.
+func (k *KeyMap) LoadKeyBindings(path string) []string {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("cannot read %s: %v", path, err)}
+	}
+
+	var config map[string]keyList
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return []string{fmt.Sprintf("%s is not valid YAML, using the default keys: %v", path, err)}
+	}
+
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bindings := k.configurableBindings()
+	var warnings []string
+	for _, name := range names {
+		keys := config[name]
+		binding, ok := bindings[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown binding %q", path, name))
+			continue
+		}
+		if err := validateKeys(keys); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s: %v, using the default keys", path, name, err))
+			continue
+		}
+
+		if name == "quit" && !containsKey(keys, "ctrl+c") {
+			// Keep a way out whatever the configuration says
+			keys = append(keys, "ctrl+c")
+		}
+		// Bubble Tea reports the space bar as " ", which is easy to miss in YAML
+		matched := make([]string, len(keys))
+		for i, k := range keys {
+			matched[i] = k
+			if k == "space" {
+				matched[i] = " "
+			}
+		}
+		*binding = key.NewBinding(
+			key.WithKeys(matched...),
+			key.WithHelp(strings.Join(keys, "/"), binding.Help().Desc),
+		)
+	}
+	return warnings
+}
+
+// validateKeys checks that every key is one Bubble Tea can report
+func validateKeys(keys keyList) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys given")
+	}
+	for _, k := range keys {
+		if !validKey(k) {
+			return fmt.Errorf("%q is not a key name", k)
+		}
+	}
+	return nil
+}
+
+// validKey reports whether name is a single character, a named key, or one
+// of those with ctrl+ or alt+ in front
+func validKey(name string) bool {
+	if name == "space" {
+		return true
+	}
+	name = strings.TrimPrefix(name, "alt+")
+	name = strings.TrimPrefix(name, "ctrl+")
+	if utf8.RuneCountInString(name) == 1 {
+		return true
+	}
+	if namedKeys[name] {
+		return true
+	}
+	// Function keys, f1 to f20
+	var n int
+	if _, err := fmt.Sscanf(name, "f%d", &n); err == nil && n >= 1 && n <= 20 {
+		return name == fmt.Sprintf("f%d", n)
+	}
+	return false
+}
+
+// containsKey reports whether keys includes name
+func containsKey(keys []string, name string) bool {
+	for _, k := range keys {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// LinkItem is a link in the links overlay
+type LinkItem struct {
+	sparktype.Link
+	Number  int
+	Path    string // Content path of an internal link, or "" for external links
+	Page    string // Title of the page an internal link leads to
+	Heading string // Heading an anchor link on the same page leads to
+	Line    int    // Line of that heading in the displayed content, or -1
+}
+
+// Title returns the numbered link text
+func (l LinkItem) Title() string {
+	return fmt.Sprintf("%d. %s", l.Number, l.Text)
+}
+
+// Description returns where the link leads
+func (l LinkItem) Description() string {
+	if l.Heading != "" {
+		return "↓ " + l.Heading
+	}
+	if strings.HasPrefix(l.Destination, "#") {
+		return "↓ " + l.Destination
+	}
+	if l.Path != "" {
+		return "→ " + l.Page
+	}
+	return "↗ " + l.Destination
+}
+
+// FilterValue returns the value to filter on
+func (l LinkItem) FilterValue() string {
+	return l.Text
+}
+
+// showLinks opens the list of links on the current page
+func (a *App) showLinks() (tea.Model, tea.Cmd) {
+	if a.content == nil {
+		return a, nil
+	}
+
+	links := a.renderer.ExtractLinks(a.content.Content)
+	if len(links) == 0 {
+		return a, a.setStatus(tr("This page has no links"))
+	}
+
+	headings := a.renderer.ExtractHeadings(a.content.Content)
+	items := make([]list.Item, len(links))
+	for i, link := range links {
+		item := LinkItem{Link: link, Number: i + 1, Line: -1}
+		if id, ok := strings.CutPrefix(link.Destination, "#"); ok {
+			item.Heading, item.Line = a.anchorTarget(headings, id)
+		} else if path := a.linkTarget(link.Destination); path != "" {
+			item.Path = path
+			item.Page = path
+			if title := a.titleForPath(path); title != "" {
+				item.Page = title
+			}
+		}
+		items[i] = item
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.linkList = list.New(items, delegate, a.width, a.height-4)
+	a.linkList.Title = tr("Links")
+	a.linkList.SetShowStatusBar(false)
+	a.linkList.SetShowHelp(false)
+
+	a.state = StateLinks
+	return a, nil
+}
+
+// followLink opens the link with the given index: pages on the site are
+// loaded in place and anything else opens in the browser
+func (a *App) followLink(index int) (tea.Model, tea.Cmd) {
+	items := a.linkList.Items()
+	if index < 0 || index >= len(items) {
+		return a, nil
+	}
+	item, ok := items[index].(LinkItem)
+	if !ok {
+		return a, nil
+	}
+
+	if strings.HasPrefix(item.Destination, "#") {
+		a.state = StateContentView
+		if item.Line < 0 {
+			return a, a.setStatus(tr("Couldn't find that heading on the page"))
+		}
+		a.viewport.SetYOffset(item.Line)
+		return a, nil
+	}
+	if item.Path == "" {
+		a.state = StateContentView
+		return a, a.openURL(a.client.ResolveURL(item.Destination))
+	}
+	a.beginLoading()
+	return a, a.loadContent(item.Path)
+}
+
+// anchorTarget returns the text of the heading with the given ID and the
+// line it is on in the displayed content, the one the contents view scrolls
+// to, or a line of -1 if the page has no such heading
+func (a *App) anchorTarget(headings []sparktype.Heading, id string) (string, int) {
+	if unescaped, err := url.PathUnescape(id); err == nil {
+		id = unescaped
+	}
+	// Headings with the same text are told apart by their order on the page
+	occurrence := map[string]int{}
+	for _, h := range headings {
+		if h.ID == id {
+			return h.Text, headingLine(a.contentLines, h.Text, occurrence[h.Text])
+		}
+		occurrence[h.Text]++
+	}
+	return "", -1
+}
+
+// linkTarget returns the content path an internal link leads to, or "" if
+// the link leaves the site or doesn't match anything in the manifest
+func (a *App) linkTarget(destination string) string {
+	if a.manifest == nil {
+		return ""
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil {
+		return ""
+	}
+	if u.IsAbs() {
+		base := strings.TrimSuffix(a.client.GetBaseURL(), "/")
+		if base == "" || !strings.HasPrefix(destination, base+"/") {
+			return ""
+		}
+		if u, err = url.Parse(strings.TrimPrefix(destination, base)); err != nil {
+			return ""
+		}
+	}
+	if u.Path == "" {
+		return ""
+	}
+
+	// Relative links may be written against the page's URL on the site or
+	// against its markdown file, so try both
+	candidates := []string{u.Path}
+	if !strings.HasPrefix(u.Path, "/") {
+		candidates = nil
+		for _, base := range []string{a.currentPageURL(), "/" + a.currentPath} {
+			if baseURL, err := url.Parse(base); err == nil {
+				candidates = append(candidates, baseURL.ResolveReference(&url.URL{Path: u.Path}).Path)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if path := a.pathForLink(candidate); path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// currentPageURL returns the site-relative URL of the page being viewed
+func (a *App) currentPageURL() string {
+	item, page := a.lookupPath(a.currentPath)
+	switch {
+	case item != nil && item.URL != "":
+		return item.URL
+	case page != nil && page.Slug != "":
+		return "/" + page.Slug
+	}
+	return "/" + a.currentPath
+}
+
+// pathForLink returns the content path of the page or collection item a
+// site-relative link points at, matching its URL, slug or file path
+func (a *App) pathForLink(link string) string {
+	want := linkKey(link)
+	for _, ref := range a.manifest.AllContent() {
+		names := []string{ref.Path, strings.TrimPrefix(ref.Path, "content/")}
+		item, page := a.lookupPath(ref.Path)
+		if item != nil && item.URL != "" {
+			names = append(names, item.URL)
+		}
+		if page != nil && page.Slug != "" {
+			names = append(names, page.Slug)
+		}
+		for _, name := range names {
+			if linkKey(name) == want {
+				return ref.Path
+			}
+		}
+	}
+	return ""
+}
+
+// titleForPath returns the manifest title of the content at a path
+func (a *App) titleForPath(path string) string {
+	item, page := a.lookupPath(path)
+	switch {
+	case item != nil:
+		return item.Title
+	case page != nil:
+		return page.Title
+	}
+	return ""
+}
+
+// linkKey reduces a link or path to a form in which the different ways of
+// writing a page's address compare equal: "/about/", "about.html",
+// "about/index.html" and "_site/about.md" all become "about"
+func linkKey(link string) string {
+	key := strings.Trim(link, "/")
+	key = strings.TrimPrefix(key, "_site/")
+	for _, suffix := range []string{".html", ".md"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	if key == "index" {
+		return ""
+	}
+	return strings.TrimSuffix(key, "/index")
+}
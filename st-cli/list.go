@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"st-cli/pkg/sparktype"
+)
+
+// listPage is a page in the JSON listing, with its nested pages
+type listPage struct {
+	Title    string     `json:"title"`
+	Type     string     `json:"type,omitempty"`
+	Path     string     `json:"path"`
+	Slug     string     `json:"slug"`
+	Children []listPage `json:"children,omitempty"`
+}
+
+// listItem is a collection item in the JSON listing
+type listItem struct {
+	Title        string `json:"title"`
+	Path         string `json:"path"`
+	Slug         string `json:"slug"`
+	URL          string `json:"url,omitempty"`
+	CollectionID string `json:"collectionId"`
+}
+
+// listCollection is a collection and its items in the JSON listing
+type listCollection struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	ContentPath string     `json:"contentPath"`
+	Items       []listItem `json:"items"`
+}
+
+// siteListing is the JSON document printed by `st-cli list`
+type siteListing struct {
+	SiteID           string           `json:"siteId"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description,omitempty"`
+	GeneratorVersion string           `json:"generatorVersion,omitempty"`
+	Pages            []listPage       `json:"pages"`
+	Collections      []listCollection `json:"collections"`
+}
+
+// buildListing converts a manifest into its JSON listing
+func buildListing(manifest *sparktype.SiteManifest) siteListing {
+	var convertPages func(items []sparktype.MenuItem) []listPage
+	convertPages = func(items []sparktype.MenuItem) []listPage {
+		pages := make([]listPage, 0, len(items))
+		for _, item := range items {
+			pages = append(pages, listPage{
+				Title:    item.Title,
+				Type:     item.Type,
+				Path:     item.Path,
+				Slug:     item.Slug,
+				Children: convertPages(item.Children),
+			})
+		}
+		return pages
+	}
+
+	collections := make([]listCollection, 0, len(manifest.Collections))
+	for _, collection := range manifest.Collections {
+		items := []listItem{}
+		for _, item := range manifest.CollectionItems {
+			if item.CollectionID != collection.ID {
+				continue
+			}
+			items = append(items, listItem{
+				Title:        item.Title,
+				Path:         item.Path,
+				Slug:         item.Slug,
+				URL:          item.URL,
+				CollectionID: item.CollectionID,
+			})
+		}
+		collections = append(collections, listCollection{
+			ID:          collection.ID,
+			Name:        collection.Name,
+			ContentPath: collection.ContentPath,
+			Items:       items,
+		})
+	}
+
+	return siteListing{
+		SiteID:           manifest.SiteID,
+		Title:            manifest.Title,
+		Description:      manifest.Description,
+		GeneratorVersion: manifest.GeneratorVersion,
+		Pages:            convertPages(manifest.Structure),
+		Collections:      collections,
+	}
+}
+
+// runList implements `st-cli list`, printing the site's navigation tree and
+// collections as JSON to out
+func runList(args []string, out io.Writer) int {
+	config := DefaultConfig()
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: st-cli list [flags] <site-url>")
+		fs.PrintDefaults()
+	}
+	config.RegisterClientFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	client, err := config.NewClient(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, err := client.FetchManifest(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if err := writeListing(out, manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeListing writes the site's navigation tree and collections to out as
+// indented JSON
+func writeListing(out io.Writer, manifest *sparktype.SiteManifest) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildListing(manifest))
+}
@@ -1,27 +1,88 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
+// commands are the non-interactive subcommands, keyed by name. Each takes
+// the arguments following its name and the writer for its output, and
+// returns the process exit code. Errors go to stderr.
+var commands = map[string]func(args []string, out io.Writer) int{
+	"export":     runExport,
+	"cat":        runCat,
+	"list":       runList,
+	"feed":       runFeed,
+	"snapshot":   runSnapshot,
+	"completion": runCompletion,
+}
+
+// browserFlags registers the flags of the interactive browser
+func browserFlags(fs *flag.FlagSet, config *Config) {
+	config.RegisterBrowserFlags(fs)
+	config.RegisterRenderFlags(fs)
+	config.RegisterClientFlags(fs)
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: st-cli <site-url>")
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:], os.Stdout))
+		}
+	}
+
+	config := DefaultConfig()
+
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "Usage: st-cli [flags] <site-url | site-directory | snapshot.zip>")
+		fmt.Fprintln(out, "       st-cli cat [flags] <site-url> <path>")
+		fmt.Fprintln(out, "       st-cli list [flags] <site-url>")
+		fmt.Fprintln(out, "       st-cli export [flags] <site-url> <dir>")
+		fmt.Fprintln(out, "       st-cli feed [flags] <site-url> <collection-id>")
+		fmt.Fprintln(out, "       st-cli snapshot [flags] <site-url> <file.zip>")
+		fmt.Fprintln(out, "       st-cli completion <bash|zsh|fish>")
+		flag.PrintDefaults()
+	}
+	browserFlags(flag.CommandLine, &config)
+	parseFlags(flag.CommandLine, os.Args[1:])
+
+	if flag.NArg() < 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	siteURL := os.Args[1]
+	siteURL := flag.Arg(0)
+
+	if config.NoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	setLocale(config.Lang)
+	if config.Verbose && config.LogFile == "" {
+		// The browser's screen hides stderr, so its log goes to a file
+		config.LogFile = DefaultLogFile()
+	}
+
+	for _, warning := range keys.LoadKeyBindings(filepath.Join(DefaultConfigDir(), "keys.yaml")) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
 
 	// Initialize the application with the site URL
-	app := NewApp(siteURL)
+	app := NewApp(siteURL, config)
 
 	// Start the Bubble Tea program
 	p := tea.NewProgram(app, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	app.savePositions()
+	if err != nil {
 		log.Fatal(err)
 	}
-}
\ No newline at end of file
+}
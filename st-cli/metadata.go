@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metadataRow is one frontmatter field in the metadata panel, with nested
+// fields named by their path, as in "banner_image.src"
+type metadataRow struct {
+	Key   string
+	Value string
+}
+
+// flattenMetadata lists the fields of a page's frontmatter in key order.
+// Nested maps become one row per field, and lists of maps one row per field
+// of each entry, as in "authors[0].name"; lists of plain values are joined.
+func flattenMetadata(prefix string, value interface{}) []metadataRow {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for name := range v {
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+
+		var rows []metadataRow
+		for _, name := range keys {
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			rows = append(rows, flattenMetadata(path, v[name])...)
+		}
+		if len(rows) == 0 {
+			rows = append(rows, metadataRow{Key: prefix, Value: "{}"})
+		}
+		return rows
+
+	case []interface{}:
+		nested := false
+		values := make([]string, len(v))
+		for i, entry := range v {
+			if _, ok := entry.(map[string]interface{}); ok {
+				nested = true
+			}
+			values[i] = metadataValue(entry)
+		}
+		if !nested {
+			return []metadataRow{{Key: prefix, Value: strings.Join(values, ", ")}}
+		}
+
+		var rows []metadataRow
+		for i, entry := range v {
+			rows = append(rows, flattenMetadata(fmt.Sprintf("%s[%d]", prefix, i), entry)...)
+		}
+		return rows
+	}
+	return []metadataRow{{Key: prefix, Value: metadataValue(value)}}
+}
+
+// metadataValue formats a plain frontmatter value. Dates are shown without a
+// time of day when they have none.
+func metadataValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "(empty)"
+	case string:
+		return v
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// renderMetadata draws a page's frontmatter as a two-column table, width
+// columns wide, with long values wrapped
+func renderMetadata(metadata map[string]interface{}, width int) string {
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(0, 1)
+	// The border and padding take two columns each side
+	inner := width - 4
+	if inner < 20 {
+		inner = 20
+	}
+
+	rows := flattenMetadata("", metadata)
+	if len(metadata) == 0 {
+		return border.Render(helpStyle.Width(inner).Render(tr("This page has no frontmatter")))
+	}
+
+	keyWidth := 0
+	for _, row := range rows {
+		if w := lipgloss.Width(row.Key); w > keyWidth {
+			keyWidth = w
+		}
+	}
+	// Long keys are wrapped rather than leaving no room for the values
+	if keyWidth > inner/3 {
+		keyWidth = inner / 3
+	}
+	keyStyle := lipgloss.NewStyle().Bold(true).Width(keyWidth + 2)
+	valueStyle := lipgloss.NewStyle().Width(inner - keyWidth - 2)
+
+	lines := []string{helpGroupStyle.Render(tr("Frontmatter"))}
+	for _, row := range rows {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			keyStyle.Render(row.Key),
+			valueStyle.Render(row.Value),
+		))
+	}
+	return border.Render(strings.Join(lines, "\n"))
+}
+
+// toggleMetadata shows or hides the frontmatter panel above the page
+func (a *App) toggleMetadata() (tea.Model, tea.Cmd) {
+	// Rebuilding the view also scrolls back to the top
+	a.showMetadata = !a.showMetadata
+	a.setupContentView()
+	return a, nil
+}
@@ -2,11 +2,34 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"st-cli/pkg/sparktype"
 )
 
+// NavigationItem represents an item in the UI navigation tree
+type NavigationItem struct {
+	Title        string
+	Description  string
+	Type         string // "page", "item"
+	Path         string
+	IsSelected   bool
+	Level        int                  // For indentation
+	ParentPath   string               // For hierarchical navigation
+	CollectionID string               // For collection items
+	Date         time.Time            // For sorting
+	Children     []sparktype.MenuItem // Nested pages, shown in a submenu when selected
+}
+
 // NavigationItemWrapper wraps NavigationItem for the list component
 type NavigationItemWrapper struct {
 	NavigationItem
+	Index int // Position of the item in the menu, which filtering hides
 }
 
 // Title returns the title for the list item
@@ -26,11 +49,15 @@ func (n NavigationItemWrapper) FilterValue() string {
 	return n.NavigationItem.Title
 }
 
+// bannerMarker follows the date of collection items that have a banner image
+const bannerMarker = "📷"
+
 // CollectionItemWrapper wraps CollectionItem for the list component
 type CollectionItemWrapper struct {
-	CollectionItem
+	sparktype.CollectionItem
 	ItemDate        string
 	ItemDescription string
+	HasBanner       bool // The item's frontmatter has a banner_image
 }
 
 // Title returns the title for the collection item
@@ -40,10 +67,15 @@ func (c CollectionItemWrapper) Title() string {
 
 // Description returns the description for the collection item
 func (c CollectionItemWrapper) Description() string {
-	if c.ItemDate != "" && c.ItemDescription != "" {
-		return fmt.Sprintf("%s\n%s", c.ItemDate, c.ItemDescription)
-	} else if c.ItemDate != "" {
-		return c.ItemDate
+	date := c.ItemDate
+	if c.HasBanner {
+		date = strings.TrimSpace(date + " " + bannerMarker)
+	}
+
+	if date != "" && c.ItemDescription != "" {
+		return fmt.Sprintf("%s\n%s", date, c.ItemDescription)
+	} else if date != "" {
+		return date
 	} else if c.ItemDescription != "" {
 		return c.ItemDescription
 	}
@@ -61,19 +93,140 @@ func (a *App) buildNavigationItems() {
 		return
 	}
 
+	a.navigationHistory = nil
+	a.navigationCursor = 0
+	if a.treeMode {
+		a.navigationItems = a.treeItems()
+		return
+	}
+
 	var items []NavigationItem
 
 	// Add regular pages from structure
 	for _, menuItem := range a.manifest.Structure {
 		items = append(items, NavigationItem{
-			Title: menuItem.Title,
-			Type:  "page",
-			Path:  menuItem.Path,
-			Level: 0,
+			Title:       menuItem.Title,
+			Description: a.pageDescription(menuItem.Path),
+			Type:        "page",
+			Path:        menuItem.Path,
+			Level:       0,
+			Children:    menuItem.Children,
+		})
+	}
+
+	a.navigationItems = items
+	a.navigationHistory = []navigationEntry{{items: items}}
+}
+
+// navigationEntry is a place in the navigation history: a menu, or a page
+// or collection opened from the menu before it
+type navigationEntry struct {
+	items []NavigationItem // Menu shown, for a menu
+	page  *NavigationItem  // Page or collection opened, otherwise
+}
+
+// pageDescription returns the menu description for a page: the number of
+// items in the collection it lists, if any
+func (a *App) pageDescription(path string) string {
+	collection, ok := a.manifest.CollectionForPage(path)
+	if !ok {
+		return ""
+	}
+	return itemCount(a.manifest.CollectionSize(collection.ID))
+}
+
+// itemCount describes a number of collection items
+func itemCount(n int) string {
+	if n == 1 {
+		return tr("1 item")
+	}
+	return fmt.Sprintf(tr("%d items"), n)
+}
+
+// enterSubmenu replaces the menu with a page and its children, remembering
+// the current menu so that back returns to it. Like a browser, entering a
+// new submenu discards the places that forward would have returned to.
+func (a *App) enterSubmenu(parent NavigationItem) {
+	// The page itself comes first so it can still be opened
+	items := []NavigationItem{{
+		Title:       parent.Title,
+		Description: parent.Description,
+		Type:        parent.Type,
+		Path:        parent.Path,
+		Level:       0,
+		ParentPath:  parent.ParentPath,
+	}}
+	for _, child := range parent.Children {
+		items = append(items, NavigationItem{
+			Title:       child.Title,
+			Description: a.pageDescription(child.Path),
+			Type:        "page",
+			Path:        child.Path,
+			Level:       1,
+			ParentPath:  parent.Path,
+			Children:    child.Children,
 		})
 	}
 
+	a.navigationHistory = append(a.navigationHistory[:a.navigationCursor+1], navigationEntry{items: items})
+	a.navigationCursor++
 	a.navigationItems = items
+	a.setupUI()
+}
+
+// leaveSubmenu returns to the menu the current submenu was entered from,
+// reporting false if already at the top level
+func (a *App) leaveSubmenu() bool {
+	if !a.inSubmenu() {
+		return false
+	}
+
+	a.navigationCursor--
+	a.navigationItems = a.navigationHistory[a.navigationCursor].items
+	a.setupUI()
+	return true
+}
+
+// visitPage records a page or collection opened from the current menu, so
+// that forward reopens it once back has returned to the menu. Like entering
+// a submenu, it discards the places forward would have returned to.
+func (a *App) visitPage(page NavigationItem) {
+	if a.navigationCursor >= len(a.navigationHistory) {
+		// The tree keeps no history
+		return
+	}
+	a.navigationHistory = append(a.navigationHistory[:a.navigationCursor+1], navigationEntry{page: &page})
+}
+
+// canGoForward reports whether forward has a submenu or page to return to
+func (a *App) canGoForward() bool {
+	return a.navigationCursor+1 < len(a.navigationHistory)
+}
+
+// goForward re-enters the submenu, or reopens the page or collection, most
+// recently left with back
+func (a *App) goForward() (tea.Model, tea.Cmd) {
+	if !a.canGoForward() {
+		return a, nil
+	}
+
+	next := a.navigationHistory[a.navigationCursor+1]
+	switch {
+	case next.page == nil:
+		a.navigationCursor++
+		a.navigationItems = next.items
+		a.setupUI()
+		return a, nil
+	case next.page.Type == "collection":
+		return a.openCollection(*next.page)
+	}
+	a.beginLoading()
+	return a, a.loadContent(next.page.Path)
+}
+
+// inSubmenu reports whether the main menu is showing a submenu
+func (a *App) inSubmenu() bool {
+	return a.navigationCursor > 0
 }
 
 // showCollectionItems shows collection items under a parent page
@@ -83,7 +236,7 @@ func (a *App) showCollectionItems(parentPath, collectionID string) {
 	}
 
 	// Get items for this collection and sort by date (most recent first)
-	var collectionItems []CollectionItem
+	var collectionItems []sparktype.CollectionItem
 	for _, item := range a.manifest.CollectionItems {
 		if item.CollectionID == collectionID {
 			collectionItems = append(collectionItems, item)
@@ -118,23 +271,154 @@ func (a *App) showCollectionItems(parentPath, collectionID string) {
 	a.navigationItems = items
 }
 
-// sortCollectionItemsByDate sorts collection items by date (most recent first)
-func (a *App) sortCollectionItemsByDate(items []CollectionItem) {
-	// Sort items by fetching their dates
-	// This is a simplified implementation - in practice you might want to cache dates
-	for i := 0; i < len(items); i++ {
-		for j := i + 1; j < len(items); j++ {
-			// Fetch dates for comparison
-			content1, err1 := a.client.FetchContent(items[i].Path)
-			content2, err2 := a.client.FetchContent(items[j].Path)
-
-			// Compare dates (most recent first)
-			if err1 == nil && err2 == nil {
-				if content1.Date.Before(content2.Date) {
-					// Swap items
-					items[i], items[j] = items[j], items[i]
-				}
+// lookupPath finds the collection item or page at a content path in the manifest
+func (a *App) lookupPath(path string) (*sparktype.CollectionItem, *sparktype.MenuItem) {
+	if a.manifest == nil {
+		return nil, nil
+	}
+
+	for i := range a.manifest.CollectionItems {
+		if a.manifest.CollectionItems[i].Path == path {
+			return &a.manifest.CollectionItems[i], nil
+		}
+	}
+
+	var findPage func(items []sparktype.MenuItem) *sparktype.MenuItem
+	findPage = func(items []sparktype.MenuItem) *sparktype.MenuItem {
+		for i := range items {
+			if items[i].Path == path {
+				return &items[i]
+			}
+			if page := findPage(items[i].Children); page != nil {
+				return page
 			}
 		}
+		return nil
+	}
+	return nil, findPage(a.manifest.Structure)
+}
+
+// urlForPath returns the canonical URL of the page or collection item at a
+// content path, or an empty string if the site doesn't say where it lives
+func (a *App) urlForPath(path string) string {
+	if a.client.IsLocal() {
+		return ""
+	}
+
+	item, page := a.lookupPath(path)
+	switch {
+	case item != nil && item.URL != "":
+		return a.client.ResolveURL(item.URL)
+	case page != nil && page.Slug != "":
+		return a.client.ResolveURL("/" + page.Slug)
 	}
-}
\ No newline at end of file
+	return ""
+}
+
+// slugForPath returns the slug the manifest gives the content at a path,
+// falling back to the file name
+func (a *App) slugForPath(path string) string {
+	item, page := a.lookupPath(path)
+	switch {
+	case item != nil && item.Slug != "":
+		return item.Slug
+	case page != nil && page.Slug != "":
+		return page.Slug
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// sortCollectionItemsByDate sorts collection items by date (most recent first)
+func (a *App) sortCollectionItemsByDate(items []sparktype.CollectionItem) {
+	// Fetch each item's date exactly once, picking up its tags on the way;
+	// items whose content can't be fetched keep a zero date and sort last
+	for i := range items {
+		if !items[i].Date.IsZero() {
+			continue
+		}
+		if content, err := a.client.FetchContent(a.ctx, items[i].Path); err == nil {
+			items[i].Date = content.Date
+			items[i].Tags = content.Tags
+			items[i].Draft = content.Draft
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+}
+
+// collectionSort is an order for the items in a collection listing
+type collectionSort int
+
+const (
+	sortNewest collectionSort = iota
+	sortOldest
+	sortTitle
+)
+
+// String describes the sort order for the help line
+func (s collectionSort) String() string {
+	switch s {
+	case sortOldest:
+		return "oldest first"
+	case sortTitle:
+		return "by title"
+	}
+	return "newest first"
+}
+
+// next returns the order that follows s when cycling through them
+func (s collectionSort) next() collectionSort {
+	return (s + 1) % 3
+}
+
+// withoutDrafts removes draft items from a sorted list of collection items,
+// unless drafts are being shown. Drafts are known once the items have been
+// sorted, which fetches their frontmatter.
+func (a *App) withoutDrafts(items []sparktype.CollectionItem) []sparktype.CollectionItem {
+	if a.showDrafts {
+		return items
+	}
+	var published []sparktype.CollectionItem
+	for _, item := range items {
+		if !item.Draft {
+			published = append(published, item)
+		}
+	}
+	return published
+}
+
+// sortCollectionItems sorts collection items in the order chosen for
+// listings. Undated items come last whichever way dates are sorted.
+func (a *App) sortCollectionItems(items []sparktype.CollectionItem) {
+	a.sortCollectionItemsByDate(items)
+
+	switch a.collectionSort {
+	case sortOldest:
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Date.IsZero() || items[j].Date.IsZero() {
+				return !items[i].Date.IsZero() && items[j].Date.IsZero()
+			}
+			return items[i].Date.Before(items[j].Date)
+		})
+	case sortTitle:
+		sort.SliceStable(items, func(i, j int) bool {
+			return strings.ToLower(items[i].Title) < strings.ToLower(items[j].Title)
+		})
+	}
+
+	if a.groupByYear {
+		a.sortByYearGroup(items)
+	}
+}
+
+// cycleCollectionSort switches the listing to the next sort order and
+// returns to its first page
+func (a *App) cycleCollectionSort() (tea.Model, tea.Cmd) {
+	a.collectionSort = a.collectionSort.next()
+	a.sortCollectionItems(a.collectionAll)
+	a.applyCollectionFilter()
+	a.setupCollectionListingUI()
+	return a, a.setStatus(fmt.Sprintf(tr("Sorted %s"), tr(a.collectionSort.String())))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// numberTimeout is how long to wait for a second digit before selecting
+// the item numbered by the first
+const numberTimeout = 750 * time.Millisecond
+
+// NumberTimeoutMsg selects the pending item number once no more digits
+// have been typed
+type NumberTimeoutMsg struct {
+	id int
+}
+
+// isDigitKey reports whether the key is a single digit
+func isDigitKey(msg tea.KeyMsg) bool {
+	s := msg.String()
+	return len(s) == 1 && s[0] >= '0' && s[0] <= '9'
+}
+
+// numberedItemCount returns how many items in the current view can be
+// selected by number
+func (a *App) numberedItemCount() int {
+	switch a.state {
+	case StateMainMenu:
+		return len(a.navigationItems)
+	case StateCollectionListing:
+		return len(a.getCurrentPageItems())
+	case StateLinks:
+		return len(a.linkList.Items())
+	}
+	return 0
+}
+
+// handleNumberKey adds a digit to the item number being typed. The item is
+// selected as soon as no further digit could lead to another item, and
+// otherwise after a short wait.
+func (a *App) handleNumberKey(digit string) (tea.Model, tea.Cmd) {
+	number := a.pendingNumber + digit
+	n, _ := strconv.Atoi(number)
+	count := a.numberedItemCount()
+	if n == 0 || n > count {
+		a.pendingNumber = ""
+		return a, nil
+	}
+
+	if n*10 > count {
+		a.pendingNumber = ""
+		return a.selectNumber(n)
+	}
+
+	a.pendingNumber = number
+	a.pendingNumberID++
+	id := a.pendingNumberID
+	return a, tea.Tick(numberTimeout, func(time.Time) tea.Msg {
+		return NumberTimeoutMsg{id: id}
+	})
+}
+
+// handleNumberTimeout selects the pending item number if no more digits
+// have been typed since
+func (a *App) handleNumberTimeout(msg NumberTimeoutMsg) (tea.Model, tea.Cmd) {
+	if msg.id != a.pendingNumberID || a.pendingNumber == "" {
+		return a, nil
+	}
+	n, _ := strconv.Atoi(a.pendingNumber)
+	a.pendingNumber = ""
+	return a.selectNumber(n)
+}
+
+// selectNumber selects the item with the given 1-based number
+func (a *App) selectNumber(n int) (tea.Model, tea.Cmd) {
+	switch a.state {
+	case StateMainMenu:
+		return a.selectNavigationItem(n - 1)
+	case StateCollectionListing:
+		if pageItems := a.getCurrentPageItems(); n <= len(pageItems) {
+			return a.selectCollectionItem(pageItems[n-1])
+		}
+	case StateLinks:
+		return a.followLink(n - 1)
+	}
+	return a, nil
+}
+
+// pendingNumberStatus describes the item number being typed
+func (a *App) pendingNumberStatus() string {
+	return fmt.Sprintf(tr("Item %s… (type another digit, or wait to select)"), a.pendingNumber)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startPageJump opens the prompt for a page number in the collection listing
+func (a *App) startPageJump() (tea.Model, tea.Cmd) {
+	if a.totalPages <= 1 {
+		return a, a.setStatus(tr("There is only one page"))
+	}
+
+	a.pageInput = textinput.New()
+	a.pageInput.Prompt = tr("Go to page: ")
+	a.pageInput.Placeholder = fmt.Sprintf("1-%d", a.totalPages)
+	a.pageInput.CharLimit = len(strconv.Itoa(a.totalPages))
+	a.pageInput.Focus()
+	a.pageInputActive = true
+	return a, textinput.Blink
+}
+
+// handlePageJumpKey handles keyboard input while the page prompt is open
+func (a *App) handlePageJumpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return a.quit()
+
+	case tea.KeyEsc:
+		a.pageInputActive = false
+		return a, nil
+
+	case tea.KeyEnter:
+		a.pageInputActive = false
+		value := strings.TrimSpace(a.pageInput.Value())
+		if value == "" {
+			return a, nil
+		}
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 || page > a.totalPages {
+			return a, a.setStatus(fmt.Sprintf(tr("No page %q; pages run from 1 to %d"), value, a.totalPages))
+		}
+		a.currentPage = page
+		a.setupCollectionListingUI()
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.pageInput, cmd = a.pageInput.Update(msg)
+	return a, cmd
+}
+
+// pageJumpPrompt renders the page number input
+func (a *App) pageJumpPrompt() string {
+	return fmt.Sprintf("%s  %s", a.pageInput.View(), helpStyle.Render(tr("enter: go • esc: cancel")))
+}
+
+// jumpToLetter selects the first item whose title starts with letter in a
+// listing sorted by title, turning to the page it is on
+func (a *App) jumpToLetter(letter string) (tea.Model, tea.Cmd) {
+	target := -1
+	for i, item := range a.collectionItems {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(item.Title)), letter) {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return a, a.setStatus(fmt.Sprintf(tr("No titles start with %s"), letter))
+	}
+
+	if !a.showAllItems {
+		a.currentPage = target/a.itemsPerPage + 1
+	}
+	a.setupCollectionListingUI()
+	path := a.collectionItems[target].Path
+	for i, item := range a.list.Items() {
+		if wrapper, ok := item.(CollectionItemWrapper); ok && wrapper.Path == path {
+			a.list.Select(i)
+			break
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteCommand is an action offered by the command palette
+type paletteCommand struct {
+	Name      string
+	Binding   *key.Binding // Key that runs the action directly, if any
+	Available func(a *App) bool
+	Run       func(a *App) (tea.Model, tea.Cmd)
+}
+
+// PaletteItem wraps a command for the list component
+type PaletteItem struct {
+	Command paletteCommand
+}
+
+// Title returns the name of the command
+func (p PaletteItem) Title() string {
+	return tr(p.Command.Name)
+}
+
+// Description returns the key that runs the command without the palette
+func (p PaletteItem) Description() string {
+	if p.Command.Binding == nil || len(p.Command.Binding.Keys()) == 0 {
+		return ""
+	}
+	return tr("key: ") + p.Command.Binding.Help().Key
+}
+
+// FilterValue returns the value to filter on
+func (p PaletteItem) FilterValue() string {
+	return tr(p.Command.Name)
+}
+
+// inStates returns an availability check for commands that apply to some
+// views only
+func inStates(states ...AppState) func(a *App) bool {
+	return func(a *App) bool {
+		for _, state := range states {
+			if a.state == state {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// keyCommand adapts an action that only returns a command
+func keyCommand(run func(a *App) tea.Cmd) func(a *App) (tea.Model, tea.Cmd) {
+	return func(a *App) (tea.Model, tea.Cmd) {
+		return a, run(a)
+	}
+}
+
+// paletteCommands lists every command the palette can offer, in the order
+// they are shown
+func paletteCommands() []paletteCommand {
+	browsing := inStates(StateMainMenu, StateCollectionListing, StateContentView)
+	return []paletteCommand{
+		{
+			Name:      "Refresh",
+			Binding:   &keys.Refresh,
+			Available: browsing,
+			Run:       (*App).handleRefresh,
+		},
+		{
+			Name:      "Search the site",
+			Binding:   &keys.Search,
+			Available: browsing,
+			Run:       (*App).startSearch,
+		},
+		{
+			Name:      "Filter the list",
+			Binding:   &keys.Filter,
+			Available: (*App).listFilterable,
+			Run:       (*App).startListFilter,
+		},
+		{
+			Name:    "Open in browser",
+			Binding: &keys.Open,
+			Available: func(a *App) bool {
+				return !a.client.IsLocal() && inStates(StateCollectionListing, StateContentView)(a)
+			},
+			Run: keyCommand(func(a *App) tea.Cmd {
+				if a.state == StateCollectionListing {
+					if item, ok := a.list.SelectedItem().(CollectionItemWrapper); ok {
+						return a.openURL(a.urlForPath(item.Path))
+					}
+					return nil
+				}
+				return a.openURL(a.urlForPath(a.currentPath))
+			}),
+		},
+		{
+			Name:    "Copy page URL",
+			Binding: &keys.CopyURL,
+			Available: func(a *App) bool {
+				return !a.client.IsLocal() && inStates(StateContentView)(a)
+			},
+			Run: (*App).copyURL,
+		},
+		{
+			Name:      "Export page",
+			Binding:   &keys.Export,
+			Available: inStates(StateContentView),
+			Run:       keyCommand((*App).exportCurrentPage),
+		},
+		{
+			Name:      "Toggle raw markdown",
+			Binding:   &keys.Raw,
+			Available: inStates(StateContentView),
+			Run: func(a *App) (tea.Model, tea.Cmd) {
+				a.showRaw = !a.showRaw
+				a.setupContentView()
+				return a, nil
+			},
+		},
+		{
+			Name:      "Toggle frontmatter",
+			Binding:   &keys.Metadata,
+			Available: inStates(StateContentView),
+			Run:       (*App).toggleMetadata,
+		},
+		{
+			Name:      "Jump to footnote",
+			Binding:   &keys.Footnote,
+			Available: inStates(StateContentView),
+			Run:       (*App).jumpFootnote,
+		},
+		{
+			Name:      "Find in page",
+			Binding:   &keys.Find,
+			Available: inStates(StateContentView),
+			Run:       (*App).startFind,
+		},
+		{
+			Name:      "Table of contents",
+			Binding:   &keys.TOC,
+			Available: inStates(StateContentView),
+			Run:       (*App).showTOC,
+		},
+		{
+			Name:      "Links on the page",
+			Binding:   &keys.Links,
+			Available: inStates(StateContentView),
+			Run:       (*App).showLinks,
+		},
+		{
+			Name:    "Next item in collection",
+			Binding: &keys.NextArticle,
+			Available: func(a *App) bool {
+				return a.state == StateContentView && keys.NextArticle.Enabled()
+			},
+			Run: func(a *App) (tea.Model, tea.Cmd) { return a.openAdjacentArticle(1) },
+		},
+		{
+			Name:    "Previous item in collection",
+			Binding: &keys.PrevArticle,
+			Available: func(a *App) bool {
+				return a.state == StateContentView && keys.PrevArticle.Enabled()
+			},
+			Run: func(a *App) (tea.Model, tea.Cmd) { return a.openAdjacentArticle(-1) },
+		},
+		{
+			Name:    "Go to page",
+			Binding: &keys.GoToPage,
+			Available: func(a *App) bool {
+				return a.state == StateCollectionListing && a.totalPages > 1 && !a.showAllItems
+			},
+			Run: (*App).startPageJump,
+		},
+		{
+			Name:      "Toggle show all items",
+			Binding:   &keys.ShowAll,
+			Available: inStates(StateCollectionListing),
+			Run: func(a *App) (tea.Model, tea.Cmd) {
+				a.showAllItems = !a.showAllItems
+				a.paginate()
+				a.setupCollectionListingUI()
+				return a, nil
+			},
+		},
+		{
+			Name:      "Change sort order",
+			Binding:   &keys.Sort,
+			Available: inStates(StateCollectionListing),
+			Run:       (*App).cycleCollectionSort,
+		},
+		{
+			Name:      "Group by year",
+			Binding:   &keys.Group,
+			Available: inStates(StateCollectionListing),
+			Run:       (*App).toggleYearGroups,
+		},
+		{
+			Name:      "Filter by tag",
+			Binding:   &keys.Tags,
+			Available: inStates(StateCollectionListing),
+			Run:       (*App).showTagFilter,
+		},
+		{
+			Name:      "Toggle site tree",
+			Binding:   &keys.Tree,
+			Available: inStates(StateMainMenu),
+			Run:       (*App).toggleTreeMode,
+		},
+		{
+			Name:      "Bookmark this page",
+			Binding:   &keys.Bookmark,
+			Available: func(a *App) bool { return a.state == StateContentView && a.content != nil },
+			Run: keyCommand(func(a *App) tea.Cmd {
+				return a.toggleBookmark(a.content.Title, a.currentPath)
+			}),
+		},
+		{
+			Name:      "Show bookmarks",
+			Binding:   &keys.Bookmarks,
+			Available: browsing,
+			Run:       (*App).showBookmarks,
+		},
+		{
+			Name:      "Recently viewed",
+			Binding:   &keys.Recent,
+			Available: browsing,
+			Run:       (*App).showRecent,
+		},
+		{
+			Name:      "Site info",
+			Binding:   &keys.Info,
+			Available: func(a *App) bool { return a.manifest != nil && a.state != StateLoading && a.state != StateIndexing },
+			Run:       (*App).showInfoOverlay,
+		},
+		{
+			Name:      "Show keys",
+			Binding:   &keys.Help,
+			Available: func(a *App) bool { return true },
+			Run:       (*App).showHelpOverlay,
+		},
+		{
+			Name:      "Quit",
+			Binding:   &keys.Quit,
+			Available: func(a *App) bool { return true },
+			Run:       (*App).quit,
+		},
+	}
+}
+
+// showPalette opens the command palette over the current view
+func (a *App) showPalette() (tea.Model, tea.Cmd) {
+	a.paletteInput = textinput.New()
+	a.paletteInput.Prompt = ":"
+	a.paletteInput.Placeholder = tr("command")
+	a.paletteInput.Focus()
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.paletteList = list.New(nil, delegate, a.width, a.height-3)
+	a.paletteList.SetShowTitle(false)
+	a.paletteList.SetShowStatusBar(false)
+	a.paletteList.SetShowHelp(false)
+	a.paletteList.SetFilteringEnabled(false)
+
+	a.paletteOpen = true
+	a.filterPalette()
+	return a, textinput.Blink
+}
+
+// filterPalette lists the commands available in the current view whose
+// names, as shown, contain every word typed so far
+func (a *App) filterPalette() {
+	words := strings.Fields(strings.ToLower(a.paletteInput.Value()))
+
+	var items []list.Item
+	for _, command := range paletteCommands() {
+		if !command.Available(a) {
+			continue
+		}
+		name := strings.ToLower(tr(command.Name))
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(name, word) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			items = append(items, PaletteItem{Command: command})
+		}
+	}
+	a.paletteList.SetItems(items)
+	a.paletteList.ResetSelected()
+}
+
+// handlePaletteKey handles keyboard input while the command palette is open
+func (a *App) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return a.quit()
+
+	case tea.KeyEsc:
+		a.paletteOpen = false
+		return a, nil
+
+	case tea.KeyEnter:
+		item, ok := a.paletteList.SelectedItem().(PaletteItem)
+		if !ok {
+			return a, nil
+		}
+		a.paletteOpen = false
+		return item.Command.Run(a)
+
+	case tea.KeyUp, tea.KeyDown:
+		var cmd tea.Cmd
+		a.paletteList, cmd = a.paletteList.Update(msg)
+		return a, cmd
+	}
+
+	var cmd tea.Cmd
+	a.paletteInput, cmd = a.paletteInput.Update(msg)
+	a.filterPalette()
+	return a, cmd
+}
+
+// paletteView renders the command palette
+func (a *App) paletteView() string {
+	count := fmt.Sprintf(tr("%d commands"), len(a.paletteList.Items()))
+	if len(a.paletteList.Items()) == 1 {
+		count = tr("1 command")
+	}
+	help := helpStyle.Render(fmt.Sprintf(tr("type to filter • ↑/↓: navigate • enter: run • esc: close • %s"), count))
+	return fmt.Sprintf("%s\n%s\n%s", a.paletteInput.View(), a.paletteList.View(), help)
+}
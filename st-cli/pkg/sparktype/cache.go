@@ -0,0 +1,160 @@
+package sparktype
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached response body along with when it was fetched and
+// the ETag the server sent for it
+type cacheEntry struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	ETag      string    `json:"etag,omitempty"`
+	Body      []byte    `json:"body"`
+}
+
+// fresh reports whether the entry is younger than the given TTL
+func (e *cacheEntry) fresh(ttl time.Duration) bool {
+	return time.Since(e.FetchedAt) < ttl
+}
+
+// diskCache stores fetched response bodies on disk, keyed by a hash of the URL
+type diskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// DefaultCacheDir returns the default root directory for the content cache
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "st-cli")
+}
+
+// entryPath returns the file an entry for the given URL is stored in
+func (d *diskCache) entryPath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached entry for a URL, regardless of its age
+func (d *diskCache) get(rawURL string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(d.entryPath(rawURL))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != rawURL {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// put stores a response body and its ETag for a URL, stamped with the current time
+func (d *diskCache) put(rawURL string, body []byte, etag string) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		URL:       rawURL,
+		FetchedAt: time.Now(),
+		ETag:      etag,
+		Body:      body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(d.entryPath(rawURL), data)
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partially written file
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".st-cli-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// contentMemo holds the content files parsed during the session, keyed by
+// content path, so moving around the site doesn't reparse the same pages.
+// It is shared by copies of a client and safe for concurrent use.
+type contentMemo struct {
+	mu    sync.Mutex
+	files map[string]*ContentFile
+}
+
+// newContentMemo returns an empty memo
+func newContentMemo() *contentMemo {
+	return &contentMemo{files: make(map[string]*ContentFile)}
+}
+
+// get returns the content parsed earlier for path
+func (m *contentMemo) get(path string) (*ContentFile, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.files[path]
+	return content, ok
+}
+
+// put remembers the content parsed for path
+func (m *contentMemo) put(path string, content *ContentFile) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[path] = content
+}
+
+// clear forgets everything parsed so far
+func (m *contentMemo) clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files = make(map[string]*ContentFile)
+}
+
+// fallbackLog records when the cached copies served in place of requests
+// that couldn't reach the site were fetched. It is shared by copies of a
+// client and safe for concurrent use.
+type fallbackLog struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+// note records that a copy fetched at fetchedAt was served
+func (l *fallbackLog) note(fetchedAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.oldest.IsZero() || fetchedAt.Before(l.oldest) {
+		l.oldest = fetchedAt
+	}
+}
+
+// take returns when the oldest copy served since the last call was fetched,
+// and forgets it
+func (l *fallbackLog) take() (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	oldest := l.oldest
+	l.oldest = time.Time{}
+	return oldest, !oldest.IsZero()
+}
@@ -0,0 +1,740 @@
+package sparktype
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ContentFile // Compared without Metadata, and Date with Equal
+		wantErr bool
+	}{
+		{
+			name: "all extracted fields",
+			input: `---
+title: Hello
+layout: post
+description: A first post
+published: true
+date: 2023-05-05
+tags: [go, cli]
+categories: notes
+author: [Ada, Grace]
+layoutConfig:
+  collectionId: blog
+  layout: list
+---
+
+Body text.
+`,
+			want: ContentFile{
+				Title:        "Hello",
+				Layout:       "post",
+				Description:  "A first post",
+				Published:    true,
+				Date:         time.Date(2023, 5, 5, 0, 0, 0, 0, time.UTC),
+				Tags:         []string{"go", "cli"},
+				Categories:   []string{"notes"},
+				Author:       "Ada, Grace",
+				LayoutConfig: &LayoutConfig{CollectionID: "blog", Layout: "list"},
+				Content:      "Body text.",
+			},
+		},
+		{
+			name:  "title only",
+			input: "---\ntitle: Only a title\n---\nText",
+			want:  ContentFile{Title: "Only a title", Content: "Text"},
+		},
+		{
+			name:  "published false is a draft",
+			input: "---\npublished: false\n---\nText",
+			want:  ContentFile{Draft: true, Content: "Text"},
+		},
+		{
+			name:  "draft true",
+			input: "---\ndraft: true\npublished: true\n---\nText",
+			want:  ContentFile{Draft: true, Published: true, Content: "Text"},
+		},
+		{
+			name:  "quoted date in long form",
+			input: "---\ndate: \"May 5, 2023\"\n---\n",
+			want:  ContentFile{Date: time.Date(2023, 5, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "quoted RFC 3339 date with an offset",
+			input: "---\ndate: \"2023-05-05T10:30:00+02:00\"\n---\n",
+			want:  ContentFile{Date: time.Date(2023, 5, 5, 8, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:  "date with a time of day",
+			input: "---\ndate: \"2023-05-05 10:30:00\"\n---\n",
+			want:  ContentFile{Date: time.Date(2023, 5, 5, 10, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:  "unparseable date is left zero",
+			input: "---\ndate: sometime soon\n---\nText",
+			want:  ContentFile{Content: "Text"},
+		},
+		{
+			name:  "fields of the wrong type are skipped",
+			input: "---\ntitle: 42\nlayout: [a, b]\npublished: yes please\nlayoutConfig: flat\n---\nText",
+			want:  ContentFile{Content: "Text"},
+		},
+		{
+			name:  "empty body",
+			input: "---\ntitle: Empty\n---\n",
+			want:  ContentFile{Title: "Empty"},
+		},
+		{
+			name:  "empty frontmatter",
+			input: "---\n---\nText",
+			want:  ContentFile{Content: "Text"},
+		},
+		{
+			name:  "rules in the body are kept",
+			input: "---\ntitle: Rules\n---\nAbove\n\n---\n\nBelow",
+			want:  ContentFile{Title: "Rules", Content: "Above\n\n---\n\nBelow"},
+		},
+		{
+			name:  "windows line endings",
+			input: "---\r\ntitle: Windows\r\n---\r\nLine one\r\nLine two\r\n",
+			want:  ContentFile{Title: "Windows", Content: "Line one\nLine two"},
+		},
+		{
+			name:  "byte order mark",
+			input: "\ufeff---\ntitle: Marked\n---\nText",
+			want:  ContentFile{Title: "Marked", Content: "Text"},
+		},
+		{
+			name:  "no frontmatter, titled by its heading",
+			input: "# Plain page\n\nSome text.\n",
+			want:  ContentFile{Title: "Plain page", Content: "Some text."},
+		},
+		{
+			name:  "no frontmatter, heading inside a code block",
+			input: "```sh\n# a comment\n```\n\n# Real title\n\nText",
+			want:  ContentFile{Title: "Real title", Content: "```sh\n# a comment\n```\n\nText"},
+		},
+		{
+			name:  "no frontmatter, heading between blocks",
+			input: "Intro\n\n# Title\n\n    indented code\n",
+			want:  ContentFile{Title: "Title", Content: "Intro\n\n    indented code"},
+		},
+		{
+			name:  "no frontmatter and no heading",
+			input: "Just text.\n",
+			want:  ContentFile{Content: "Just text."},
+		},
+		{
+			name:  "empty file",
+			input: "",
+			want:  ContentFile{},
+		},
+		{
+			name:    "bad YAML",
+			input:   "---\ntitle: [unclosed\n---\nText",
+			wantErr: true,
+		},
+		{
+			name:    "frontmatter that is not a mapping",
+			input:   "---\n- a\n- b\n---\nText",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed frontmatter",
+			input:   "---\ntitle: Never closed\n\nText",
+			wantErr: true,
+		},
+	}
+
+	client := &Client{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := client.ParseMarkdown(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseMarkdown() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMarkdown() error = %v", err)
+			}
+			if got.Metadata == nil {
+				t.Error("Metadata is nil, want a map")
+			}
+			if !got.Date.Equal(test.want.Date) {
+				t.Errorf("Date = %v, want %v", got.Date, test.want.Date)
+			}
+
+			compared := *got
+			compared.Metadata = nil
+			compared.Date = test.want.Date
+			if !reflect.DeepEqual(compared, test.want) {
+				t.Errorf("ParseMarkdown() = %+v, want %+v", compared, test.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownKeepsMetadata(t *testing.T) {
+	got, err := (&Client{}).ParseMarkdown("---\ntitle: Hello\nbanner_image:\n  src: /img/a.png\n---\nText")
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if got.Metadata["title"] != "Hello" {
+		t.Errorf(`Metadata["title"] = %v, want "Hello"`, got.Metadata["title"])
+	}
+	banner, ok := got.Metadata["banner_image"].(map[string]interface{})
+	if !ok || banner["src"] != "/img/a.png" {
+		t.Errorf(`Metadata["banner_image"] = %v, want a map with src "/img/a.png"`, got.Metadata["banner_image"])
+	}
+}
+
+// testManifest is a minimal valid manifest
+const testManifest = `{"siteId": "test", "title": "Test site", "structure": [{"type": "page", "title": "About", "path": "content/about.md"}]}`
+
+// roundTripFunc is a stub transport answering requests with a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubResponse returns a response with the given status and body
+func stubResponse(req *http.Request, code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestContentURL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		path string
+		want string
+	}{
+		{
+			name: "without the prefix",
+			path: "content/about.md",
+			want: "https://example.com/docs/_site/content/about.md",
+		},
+		{
+			name: "with the prefix",
+			path: "_site/content/about.md",
+			want: "https://example.com/docs/_site/content/about.md",
+		},
+		{
+			name: "with the prefix and a leading slash",
+			path: "/_site/content/about.md",
+			want: "https://example.com/docs/_site/content/about.md",
+		},
+		{
+			name: "starting like the prefix",
+			path: "_sitemap.md",
+			want: "https://example.com/docs/_site/_sitemap.md",
+		},
+		{
+			name: "custom prefix",
+			opts: []ClientOption{WithContentPrefix("public")},
+			path: "content/about.md",
+			want: "https://example.com/docs/public/content/about.md",
+		},
+		{
+			name: "custom prefix already given",
+			opts: []ClientOption{WithContentPrefix("/public/")},
+			path: "public/content/about.md",
+			want: "https://example.com/docs/public/content/about.md",
+		},
+		{
+			name: "no prefix",
+			opts: []ClientOption{WithContentPrefix("")},
+			path: "content/about.md",
+			want: "https://example.com/docs/content/about.md",
+		},
+		{
+			name: "fully qualified URL",
+			path: "https://cdn.example.com/content/about.md",
+			want: "https://cdn.example.com/content/about.md",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewClient("https://example.com/docs/", test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.contentURL(test.path); got != test.want {
+				t.Errorf("contentURL(%q) = %q, want %q", test.path, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFetchManifestThroughTransport(t *testing.T) {
+	var paths []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		if req.URL.Path == "/manifest.json" {
+			return stubResponse(req, http.StatusOK, testManifest), nil
+		}
+		return stubResponse(req, http.StatusNotFound, ""), nil
+	})
+
+	client, err := NewClient("https://example.com", WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := client.FetchManifest(context.Background())
+	if err != nil {
+		t.Fatalf("FetchManifest() error = %v", err)
+	}
+	if manifest.Title != "Test site" {
+		t.Errorf("Title = %q, want %q", manifest.Title, "Test site")
+	}
+	if want := []string{"/_site/manifest.json", "/manifest.json"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("requested %v, want %v", paths, want)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport roundTripFunc
+		want      error
+	}{
+		{
+			name: "not found",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusNotFound, ""), nil
+			},
+			want: ErrNotFound,
+		},
+		{
+			name: "gone",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusGone, ""), nil
+			},
+			want: ErrNotFound,
+		},
+		{
+			name: "unauthorized",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusUnauthorized, ""), nil
+			},
+			want: ErrUnauthorized,
+		},
+		{
+			name: "forbidden",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusForbidden, ""), nil
+			},
+			want: ErrUnauthorized,
+		},
+		{
+			name: "connection refused",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+			want: ErrNetwork,
+		},
+		{
+			name: "unparseable manifest",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, "{not json"), nil
+			},
+			want: ErrParse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewClient("https://example.com", WithTransport(test.transport), WithManifestPath("/manifest.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.FetchManifest(context.Background())
+			if !errors.Is(err, test.want) {
+				t.Errorf("FetchManifest() error = %v, want one matching %v", err, test.want)
+			}
+		})
+	}
+}
+
+// gzipped compresses s as a server sending Content-Encoding: gzip would
+func gzipped(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestGzipResponses(t *testing.T) {
+	valid := gzipped(t, testManifest)
+	// Keep the gzip header but garble the compressed data after it
+	corrupt := valid[:10] + strings.Repeat("\xff", len(valid)-10)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{name: "compressed manifest", body: valid},
+		{name: "corrupt body", body: corrupt, wantErr: ErrParse},
+		{name: "not gzip at all", body: testManifest, wantErr: ErrParse},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if got := req.Header.Get("Accept-Encoding"); got != "gzip" {
+					t.Errorf("Accept-Encoding = %q, want gzip", got)
+				}
+				resp := stubResponse(req, http.StatusOK, test.body)
+				resp.Header.Set("Content-Encoding", "gzip")
+				return resp, nil
+			})
+			client, err := NewClient("https://example.com", WithTransport(transport), WithManifestPath("/manifest.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			manifest, err := client.FetchManifest(context.Background())
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("FetchManifest() error = %v, want one matching %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchManifest() error = %v", err)
+			}
+			if manifest.Title != "Test site" {
+				t.Errorf("Title = %q, want %q", manifest.Title, "Test site")
+			}
+		})
+	}
+}
+
+func TestFetchManifestNotASparkTypeSite(t *testing.T) {
+	const (
+		noSiteID = "the manifest has no siteId"
+		noTitle  = "the manifest has no title"
+		noPages  = "the manifest lists no pages or collections"
+	)
+	tests := []struct {
+		name         string
+		body         string
+		wantProblems []string
+		wantErr      error
+	}{
+		{
+			name: "valid",
+			body: testManifest,
+		},
+		{
+			name:         "missing siteId",
+			body:         `{"title": "Test site", "structure": [{"type": "page", "title": "About", "path": "content/about.md"}]}`,
+			wantProblems: []string{noSiteID},
+		},
+		{
+			name:         "missing title",
+			body:         `{"siteId": "test", "collections": [{"id": "blog", "name": "Blog"}]}`,
+			wantProblems: []string{noTitle},
+		},
+		{
+			name:         "no pages or collections",
+			body:         `{"siteId": "test", "title": "Test site"}`,
+			wantProblems: []string{noPages},
+		},
+		{
+			name:         "something else",
+			body:         `{"name": "something else"}`,
+			wantProblems: []string{noSiteID, noTitle, noPages},
+		},
+		{
+			name:    "not JSON",
+			body:    "<!doctype html><title>Welcome</title>",
+			wantErr: ErrParse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, test.body), nil
+			})
+			client, err := NewClient("https://example.com", WithTransport(transport), WithManifestPath("/manifest.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.FetchManifest(context.Background())
+			var manifestErr *ManifestError
+			switch {
+			case test.wantErr != nil:
+				if !errors.Is(err, test.wantErr) || errors.As(err, &manifestErr) {
+					t.Errorf("FetchManifest() error = %v, want one matching %v", err, test.wantErr)
+				}
+			case test.wantProblems == nil:
+				if err != nil {
+					t.Errorf("FetchManifest() error = %v", err)
+				}
+			case !errors.As(err, &manifestErr):
+				t.Errorf("FetchManifest() error = %v, want a *ManifestError", err)
+			case !reflect.DeepEqual(manifestErr.Problems, test.wantProblems):
+				t.Errorf("Problems = %q, want %q", manifestErr.Problems, test.wantProblems)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32 // Requests answered 503 before the server recovers
+		attempts     int
+		wantRequests int32
+		wantErr      bool
+	}{
+		{name: "recovers within the attempts", failures: 2, attempts: 3, wantRequests: 3},
+		{name: "gives up after the attempts", failures: 5, attempts: 3, wantRequests: 3, wantErr: true},
+		{name: "no retries by default", failures: 1, attempts: 0, wantRequests: 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= test.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprint(w, "---\ntitle: Retried\n---\nText")
+			}))
+			defer server.Close()
+
+			opts := []ClientOption{WithHTTPClient(server.Client())}
+			if test.attempts > 0 {
+				opts = append(opts, WithRetry(test.attempts, time.Millisecond))
+			}
+			client, err := NewClient(server.URL, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := client.FetchContent(context.Background(), "content/page.md")
+			if test.wantErr {
+				var status *StatusError
+				if !errors.As(err, &status) || status.Code != http.StatusServiceUnavailable {
+					t.Errorf("FetchContent() error = %v, want a 503 StatusError", err)
+				}
+			} else if err != nil || content.Title != "Retried" {
+				t.Errorf("FetchContent() = %+v, %v, want the page", content, err)
+			}
+			if got := atomic.LoadInt32(&requests); got != test.wantRequests {
+				t.Errorf("server saw %d requests, want %d", got, test.wantRequests)
+			}
+		})
+	}
+}
+
+func TestETagRevalidation(t *testing.T) {
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, testManifest)
+	}))
+	defer server.Close()
+
+	// Entries go stale at once, so every fetch revalidates with the ETag
+	client, err := NewClient(server.URL, WithHTTPClient(server.Client()), WithCache(t.TempDir(), time.Nanosecond), WithManifestPath("/manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		manifest, err := client.FetchManifest(context.Background())
+		if err != nil {
+			t.Fatalf("fetch %d: FetchManifest() error = %v", i+1, err)
+		}
+		if manifest.Title != "Test site" {
+			t.Errorf("fetch %d: Title = %q, want %q", i+1, manifest.Title, "Test site")
+		}
+	}
+	if requests != 3 || notModified != 2 {
+		t.Errorf("server saw %d requests, %d answered 304; want 3 and 2", requests, notModified)
+	}
+}
+
+func TestCachedFallback(t *testing.T) {
+	offline := false
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if offline {
+			return nil, errors.New("connection refused")
+		}
+		return stubResponse(req, http.StatusOK, testManifest), nil
+	})
+
+	// Entries go stale at once, so every fetch goes to the site
+	client, err := NewClient("https://example.com", WithTransport(transport), WithCache(t.TempDir(), time.Nanosecond), WithManifestPath("/manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FetchManifest(context.Background()); err != nil {
+		t.Fatalf("FetchManifest() error = %v", err)
+	}
+	if _, ok := client.TakeFallback(); ok {
+		t.Error("TakeFallback() reported a cached copy for a fetch from the site")
+	}
+
+	offline = true
+	manifest, err := client.FetchManifest(context.Background())
+	if err != nil {
+		t.Fatalf("FetchManifest() while offline error = %v, want the cached copy", err)
+	}
+	if manifest.Title != "Test site" {
+		t.Errorf("Title = %q, want %q", manifest.Title, "Test site")
+	}
+	fetchedAt, ok := client.TakeFallback()
+	if !ok || time.Since(fetchedAt) > time.Minute {
+		t.Errorf("TakeFallback() = %v, %v, want the time of the first fetch", fetchedAt, ok)
+	}
+	if _, ok := client.TakeFallback(); ok {
+		t.Error("TakeFallback() reported the cached copy twice")
+	}
+
+	// Without a cached copy the error comes through
+	if _, err := client.Get(context.Background(), "https://example.com/other.json"); !errors.Is(err, ErrNetwork) {
+		t.Errorf("Get() of an uncached URL error = %v, want ErrNetwork", err)
+	}
+}
+
+func TestAuthHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		url  func(serverURL string) string
+		opts []ClientOption
+		want string
+	}{
+		{
+			name: "none",
+			url:  func(serverURL string) string { return serverURL },
+			want: "",
+		},
+		{
+			name: "bearer token",
+			url:  func(serverURL string) string { return serverURL },
+			opts: []ClientOption{WithBearerToken("secret")},
+			want: "Bearer secret",
+		},
+		{
+			name: "basic auth",
+			url:  func(serverURL string) string { return serverURL },
+			opts: []ClientOption{WithBasicAuth("ada", "pw")},
+			want: "Basic YWRhOnB3",
+		},
+		{
+			name: "credentials in the URL",
+			url:  func(serverURL string) string { return strings.Replace(serverURL, "://", "://ada:pw@", 1) },
+			want: "Basic YWRhOnB3",
+		},
+		{
+			name: "token over basic auth",
+			url:  func(serverURL string) string { return serverURL },
+			opts: []ClientOption{WithBasicAuth("ada", "pw"), WithBearerToken("secret")},
+			want: "Bearer secret",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				fmt.Fprint(w, testManifest)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(test.url(server.URL), append(test.opts, WithHTTPClient(server.Client()))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.FetchManifest(context.Background()); err != nil {
+				t.Fatalf("FetchManifest() error = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Authorization = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsStayOnTheSite(t *testing.T) {
+	var got string
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		fmt.Fprint(w, "Hosted elsewhere")
+	}))
+	defer elsewhere.Close()
+
+	client, err := NewClient("https://example.com", WithBearerToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FetchContent(context.Background(), elsewhere.URL+"/post.md"); err != nil {
+		t.Fatalf("FetchContent() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("Authorization = %q sent to another host, want none", got)
+	}
+}
+
+func TestCustomHeaders(t *testing.T) {
+	var got http.Header
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header
+		return stubResponse(req, http.StatusOK, testManifest), nil
+	})
+	client, err := NewClient("https://example.com", WithTransport(transport), WithHeaders(http.Header{"X-Site-Key": {"abc"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FetchManifest(context.Background()); err != nil {
+		t.Fatalf("FetchManifest() error = %v", err)
+	}
+	if got.Get("X-Site-Key") != "abc" {
+		t.Errorf("X-Site-Key = %q, want %q", got.Get("X-Site-Key"), "abc")
+	}
+}
@@ -0,0 +1,5 @@
+// Package sparktype reads and renders SparkType sites. A Client fetches a
+// site's manifest and content, whether the site is served over HTTP, built
+// on local disk or saved as a snapshot, and a ContentRenderer draws its
+// pages for a terminal. The st-cli browser is built on it.
+package sparktype
@@ -0,0 +1,38 @@
+package sparktype
+
+import (
+	"regexp"
+
+	"github.com/yuin/goldmark-emoji/definition"
+)
+
+// shortcodeRegex matches GitHub emoji shortcodes such as :rocket:
+var shortcodeRegex = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+
+// githubEmoji maps GitHub's shortcodes to emoji, the same set glamour uses
+// for page bodies
+var githubEmoji = definition.Github()
+
+// WithEmoji replaces emoji shortcodes such as :rocket: with the emoji they
+// stand for, in page bodies and in titles
+func WithEmoji(enabled bool) RendererOption {
+	return func(r *ContentRenderer) {
+		r.emoji = enabled
+	}
+}
+
+// Emojify replaces the emoji shortcodes in text shown outside a page body,
+// such as a title in a list, when emoji are enabled. Unknown shortcodes are
+// left as they are.
+func (r *ContentRenderer) Emojify(text string) string {
+	if r == nil || !r.emoji {
+		return text
+	}
+	return shortcodeRegex.ReplaceAllStringFunc(text, func(shortcode string) string {
+		emoji, ok := githubEmoji.Get(shortcode[1 : len(shortcode)-1])
+		if !ok || !emoji.IsUnicode() {
+			return shortcode
+		}
+		return string(emoji.Unicode)
+	})
+}
@@ -0,0 +1,247 @@
+package sparktype
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Glamour's markdown parser can't be given extensions of its own, so the
+// ones it lacks are applied to the markdown before it is rendered, rewriting
+// their syntax into markdown glamour already draws. GFM and definition lists
+// are always on, since glamour parses them itself.
+
+// WithFootnotes collects footnotes, written [^name] with a "[^name]: note"
+// definition, into notes at the end of the page, numbered with superscripts
+func WithFootnotes(enabled bool) RendererOption {
+	return func(r *ContentRenderer) {
+		r.footnotes = enabled
+	}
+}
+
+// WithTypographer replaces straight quotes, double and triple hyphens and
+// three dots with curly quotes, dashes and ellipses, outside code
+func WithTypographer(enabled bool) RendererOption {
+	return func(r *ContentRenderer) {
+		r.typographer = enabled
+	}
+}
+
+// footnoteDefinitionRegex matches the first line of a footnote definition
+var footnoteDefinitionRegex = regexp.MustCompile(`^ {0,3}\[\^([^\]\s]+)\]:[ \t]*(.*)$`)
+
+// footnoteReferenceRegex matches a reference to a footnote
+var footnoteReferenceRegex = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+
+// byteRange is a span of the markdown source, from start up to stop
+type byteRange struct {
+	start, stop int
+}
+
+// within reports whether offset falls inside any of ranges
+func within(ranges []byteRange, offset int) bool {
+	for _, r := range ranges {
+		if offset >= r.start && offset < r.stop {
+			return true
+		}
+	}
+	return false
+}
+
+// applyExtensions rewrites the markdown for the extensions that are on
+func (r *ContentRenderer) applyExtensions(markdown string) string {
+	markdown = r.rewriteHTML(markdown)
+	if r.footnotes {
+		markdown = r.collectFootnotes(markdown)
+	}
+	if r.typographer {
+		markdown = r.smartenText(markdown)
+	}
+	return markdown
+}
+
+// codeRanges returns the parts of the markdown that are code or raw HTML,
+// which the extensions leave as they are
+func (r *ContentRenderer) codeRanges(source []byte) []byteRange {
+	var ranges []byteRange
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindFencedCodeBlock, ast.KindCodeBlock, ast.KindHTMLBlock:
+			lines := n.Lines()
+			if lines.Len() > 0 {
+				ranges = append(ranges, byteRange{lines.At(0).Start, lines.At(lines.Len() - 1).Stop})
+			}
+			return ast.WalkSkipChildren, nil
+		case ast.KindCodeSpan, ast.KindRawHTML:
+			start, stop := -1, -1
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					if start < 0 {
+						start = t.Segment.Start
+					}
+					stop = t.Segment.Stop
+				}
+			}
+			if raw, ok := n.(*ast.RawHTML); ok && raw.Segments.Len() > 0 {
+				start, stop = raw.Segments.At(0).Start, raw.Segments.At(raw.Segments.Len()-1).Stop
+			}
+			if start >= 0 {
+				// Take in the backticks, so references beside them are found
+				ranges = append(ranges, byteRange{start - 1, stop + 1})
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return ranges
+}
+
+// footnote is a footnote's definition in the markdown
+type footnote struct {
+	note   string
+	number int // Order of the footnote's first reference, from 1; 0 until referenced
+}
+
+// collectFootnotes numbers footnote references in the order they appear and
+// moves their definitions to a list of notes at the end. References to
+// undefined footnotes are left as written, and unreferenced definitions are
+// dropped.
+func (r *ContentRenderer) collectFootnotes(markdown string) string {
+	// Take out the definitions, with any indented lines continuing them
+	code := r.codeRanges([]byte(markdown))
+	footnotes := map[string]*footnote{}
+	var body strings.Builder
+	lines := strings.SplitAfter(markdown, "\n")
+	offset := 0
+	for i := 0; i < len(lines); i++ {
+		start := offset
+		offset += len(lines[i])
+		match := footnoteDefinitionRegex.FindStringSubmatch(strings.TrimRight(lines[i], "\n"))
+		if match == nil || within(code, start) {
+			body.WriteString(lines[i])
+			continue
+		}
+		note := strings.TrimSpace(match[2])
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" &&
+			(strings.HasPrefix(lines[i+1], "  ") || strings.HasPrefix(lines[i+1], "\t")) {
+			i++
+			offset += len(lines[i])
+			note += " " + strings.TrimSpace(lines[i])
+		}
+		if _, ok := footnotes[match[1]]; !ok {
+			footnotes[match[1]] = &footnote{note: note}
+		}
+	}
+	if len(footnotes) == 0 {
+		return markdown
+	}
+
+	// Number the references, now outside code in what is left
+	markdown = body.String()
+	code = r.codeRanges([]byte(markdown))
+	var notes []*footnote
+	var builder strings.Builder
+	last := 0
+	for _, match := range footnoteReferenceRegex.FindAllStringSubmatchIndex(markdown, -1) {
+		fn, ok := footnotes[markdown[match[2]:match[3]]]
+		if !ok || within(code, match[0]) {
+			continue
+		}
+		if fn.number == 0 {
+			notes = append(notes, fn)
+			fn.number = len(notes)
+		}
+		builder.WriteString(markdown[last:match[0]])
+		builder.WriteString(footnoteMarker(fn.number))
+		last = match[1]
+	}
+	builder.WriteString(markdown[last:])
+	if len(notes) == 0 {
+		return builder.String()
+	}
+
+	// Each note is a paragraph of its own, starting with its marker, which
+	// is how jumpFootnote finds them once rendered
+	result := strings.TrimRight(builder.String(), "\n") + "\n\n---\n"
+	for _, fn := range notes {
+		result += "\n" + footnoteMarker(fn.number) + " " + fn.note + "\n"
+	}
+	return result
+}
+
+// smartenText applies typographic punctuation to the prose of the markdown,
+// leaving code, links' destinations and raw HTML alone
+func (r *ContentRenderer) smartenText(markdown string) string {
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	var segments []byteRange
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan, ast.KindRawHTML, ast.KindAutoLink:
+			return ast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*ast.Text); ok {
+			segments = append(segments, byteRange{t.Segment.Start, t.Segment.Stop})
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var builder strings.Builder
+	last := 0
+	for _, segment := range segments {
+		if segment.start < last {
+			continue
+		}
+		builder.WriteString(markdown[last:segment.start])
+		builder.WriteString(smartPunctuation(markdown, segment.start, segment.stop))
+		last = segment.stop
+	}
+	builder.WriteString(markdown[last:])
+	return builder.String()
+}
+
+// smartPunctuation returns source[start:stop] with typographic dashes,
+// ellipses and quotes. Whether a quote opens or closes is decided by the
+// character before it, which may lie outside the span.
+func smartPunctuation(source string, start, stop int) string {
+	var builder strings.Builder
+	for i := start; i < stop; i++ {
+		switch {
+		case strings.HasPrefix(source[i:stop], "---"):
+			builder.WriteString("—")
+			i += 2
+		case strings.HasPrefix(source[i:stop], "--"):
+			builder.WriteString("–")
+			i++
+		case strings.HasPrefix(source[i:stop], "..."):
+			builder.WriteString("…")
+			i += 2
+		case source[i] == '"' || source[i] == '\'':
+			opening := i == 0 || strings.ContainsRune(" \t\n([{-", rune(source[i-1]))
+			switch {
+			case source[i] == '"' && opening:
+				builder.WriteString("“")
+			case source[i] == '"':
+				builder.WriteString("”")
+			case opening:
+				builder.WriteString("‘")
+			default:
+				// Apostrophes close like a single quote
+				builder.WriteString("’")
+			}
+		default:
+			builder.WriteByte(source[i])
+		}
+	}
+	return builder.String()
+}
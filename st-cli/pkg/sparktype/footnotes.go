@@ -0,0 +1,64 @@
+package sparktype
+
+import (
+	"strconv"
+	"strings"
+)
+
+// superscriptDigits are the digits 0-9 as superscripts, which number
+// footnote references and their notes
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// footnoteMarker is the text a reference to a footnote is replaced with
+func footnoteMarker(number int) string {
+	var builder strings.Builder
+	for _, digit := range strconv.Itoa(number) {
+		builder.WriteRune(superscriptDigits[digit-'0'])
+	}
+	return builder.String()
+}
+
+// superscriptValue returns the value of a superscript digit, or -1
+func superscriptValue(r rune) int {
+	for value, digit := range superscriptDigits {
+		if r == digit {
+			return value
+		}
+	}
+	return -1
+}
+
+// FootnoteMarkers returns the numbers of the footnote markers in a line of
+// plain text, in order
+func FootnoteMarkers(line string) []int {
+	var numbers []int
+	number := -1
+	for _, r := range line + " " {
+		value := superscriptValue(r)
+		switch {
+		case value >= 0 && number >= 0:
+			number = number*10 + value
+		case value >= 0:
+			number = value
+		case number >= 0:
+			numbers = append(numbers, number)
+			number = -1
+		}
+	}
+	return numbers
+}
+
+// NoteNumber returns the number of the note starting a line of plain text,
+// which collectFootnotes writes as its marker followed by a space
+func NoteNumber(line string) (int, bool) {
+	line = strings.TrimSpace(line)
+	marker, _, ok := strings.Cut(line, " ")
+	if !ok || marker == "" {
+		return 0, false
+	}
+	numbers := FootnoteMarkers(marker)
+	if len(numbers) != 1 || footnoteMarker(numbers[0]) != marker {
+		return 0, false
+	}
+	return numbers[0], true
+}
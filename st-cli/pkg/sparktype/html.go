@@ -0,0 +1,283 @@
+package sparktype
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+)
+
+// Glamour drops HTML embedded in markdown, blocks and all, so it is
+// rewritten before rendering. By default it is converted to markdown that
+// keeps its text, links and images; with WithHTML its formatting is kept
+// too, so that glamour styles it.
+
+// WithHTML renders the formatting in HTML embedded in markdown, such as bold
+// text and inline code, instead of reducing it to plain text
+func WithHTML(allowed bool) RendererOption {
+	return func(r *ContentRenderer) {
+		r.allowHTML = allowed
+	}
+}
+
+// htmlBlockElements are the elements that start and end a paragraph of
+// their own when converted
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"details": true, "div": true, "dl": true, "dt": true, "dd": true,
+	"figure": true, "figcaption": true, "footer": true, "header": true,
+	"main": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"section": true, "summary": true, "table": true, "tr": true, "ul": true,
+}
+
+// htmlHiddenElements hold no text meant for the reader
+var htmlHiddenElements = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true, "title": true,
+}
+
+// htmlMediaElements embed something from elsewhere, which is shown as a
+// link to it
+var htmlMediaElements = map[string]bool{
+	"audio": true, "embed": true, "iframe": true, "object": true, "source": true, "video": true,
+}
+
+// htmlFormatMarks are the markdown marks for the formatting elements kept
+// WithHTML
+var htmlFormatMarks = map[string]string{
+	"b": "**", "strong": "**", "i": "*", "em": "*",
+	"s": "~~", "del": "~~", "strike": "~~", "code": "`", "kbd": "`",
+}
+
+// whitespaceRegex matches runs of whitespace, which HTML shows as one space
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// blankLinesRegex matches runs of blank lines
+var blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// htmlAttr returns the value of the named attribute of a tag, or ""
+func htmlAttr(token html.Token, name string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// htmlToken returns the first token of an HTML fragment, such as a lone tag
+func htmlToken(fragment string) html.Token {
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	tokenizer.Next()
+	return tokenizer.Token()
+}
+
+// htmlTagName returns the lowercased name of a tag, as "img" for
+// `<img src="cat.png">` and "a" for `</a>`, or "" for comments
+func htmlTagName(tag string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(tag, "<"), "/")
+	end := strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end >= 0 {
+		name = name[:end]
+	}
+	return strings.ToLower(name)
+}
+
+// htmlToMarkdown converts HTML to markdown, keeping its text, links and
+// images. Embedded media become links to their source, lists become
+// markdown lists and headings markdown headings; with HTML allowed, bold,
+// italic, struck out and code text keep their marks. Other markup is
+// dropped.
+func (r *ContentRenderer) htmlToMarkdown(source string) string {
+	var builder strings.Builder
+	var links []string // Destinations of the links being written, innermost last
+	hidden := 0
+	tokenizer := html.NewTokenizer(strings.NewReader(source))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// The end of the source, or as far as it could be read
+			break
+		}
+		token := tokenizer.Token()
+		name := token.Data
+
+		switch tokenType {
+		case html.TextToken:
+			if hidden == 0 {
+				builder.WriteString(whitespaceRegex.ReplaceAllString(token.Data, " "))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch {
+			case htmlHiddenElements[name]:
+				if tokenType == html.StartTagToken {
+					hidden++
+				}
+			case hidden > 0:
+			case r.allowHTML && htmlFormatMarks[name] != "":
+				builder.WriteString(htmlFormatMarks[name])
+			case name == "img":
+				builder.WriteString(fmt.Sprintf("![%s](%s)", htmlAttr(token, "alt"), htmlAttr(token, "src")))
+			case htmlMediaElements[name]:
+				src := htmlAttr(token, "src")
+				if src == "" {
+					src = htmlAttr(token, "data")
+				}
+				if src != "" {
+					label := htmlAttr(token, "title")
+					if label == "" {
+						label = r.translate("Embedded media")
+					}
+					builder.WriteString(fmt.Sprintf("\n\n[%s](%s)\n\n", label, src))
+				}
+				// The text inside an iframe is only shown where iframes aren't
+				if name == "iframe" && tokenType == html.StartTagToken {
+					hidden++
+				}
+			case name == "a":
+				href := htmlAttr(token, "href")
+				links = append(links, href)
+				if href != "" {
+					builder.WriteString("[")
+				}
+			case name == "br":
+				builder.WriteString("\n\n")
+			case name == "hr":
+				builder.WriteString("\n\n---\n\n")
+			case name == "li":
+				builder.WriteString("\n- ")
+			case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+				builder.WriteString("\n\n" + strings.Repeat("#", int(name[1]-'0')) + " ")
+			case htmlBlockElements[name]:
+				builder.WriteString("\n\n")
+			}
+		case html.EndTagToken:
+			switch {
+			case htmlHiddenElements[name] || name == "iframe":
+				if hidden > 0 {
+					hidden--
+				}
+			case hidden > 0:
+			case r.allowHTML && htmlFormatMarks[name] != "":
+				builder.WriteString(htmlFormatMarks[name])
+			case name == "a" && len(links) > 0:
+				href := links[len(links)-1]
+				links = links[:len(links)-1]
+				if href != "" {
+					builder.WriteString(fmt.Sprintf("](%s)", href))
+				}
+			case htmlBlockElements[name], name == "li",
+				len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+				builder.WriteString("\n\n")
+			}
+		}
+	}
+
+	// Tidy the spacing left where elements met
+	lines := strings.Split(builder.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// rewriteHTML replaces the HTML embedded in the markdown, converting it to
+// markdown
+func (r *ContentRenderer) rewriteHTML(markdown string) string {
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	type replacement struct {
+		start, stop int
+		rewritten   string
+	}
+	var replacements []replacement
+	var links []string // Destinations of the inline links open, innermost last
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.HTMLBlock:
+			lines := node.Lines()
+			if lines.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			start, stop := lines.At(0).Start, lines.At(lines.Len()-1).Stop
+			if node.HasClosure() {
+				stop = node.ClosureLine.Stop
+			}
+			block := string(source[start:stop])
+
+			// Inside a list or quote the block's lines are prefixed, so what
+			// replaces it has to stay on one line
+			nested := node.Parent() != nil && node.Parent().Kind() != ast.KindDocument
+			rewritten := "\n" + r.htmlToMarkdown(block) + "\n"
+			if nested {
+				rewritten = strings.Join(strings.Fields(r.htmlToMarkdown(block)), " ")
+			}
+			if strings.HasSuffix(block, "\n") {
+				rewritten += "\n"
+			}
+			replacements = append(replacements, replacement{start, stop, rewritten})
+			return ast.WalkSkipChildren, nil
+
+		case *ast.RawHTML:
+			if node.Segments.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			start, stop := node.Segments.At(0).Start, node.Segments.At(node.Segments.Len()-1).Stop
+			tag := string(source[start:stop])
+
+			// Each tag is a node of its own, with the text between them left
+			// as markdown, so elements are converted a tag at a time
+			closing := strings.HasPrefix(tag, "</")
+			var rewritten string
+			switch name := htmlTagName(tag); {
+			case r.allowHTML && htmlFormatMarks[name] != "":
+				rewritten = htmlFormatMarks[name]
+			case name == "a" && !closing:
+				href := htmlAttr(htmlToken(tag), "href")
+				links = append(links, href)
+				if href != "" {
+					rewritten = "["
+				}
+			case name == "a" && len(links) > 0:
+				href := links[len(links)-1]
+				links = links[:len(links)-1]
+				if href != "" {
+					rewritten = fmt.Sprintf("](%s)", href)
+				}
+			case closing:
+			case name == "img":
+				rewritten = r.htmlToMarkdown(tag)
+			case htmlMediaElements[name]:
+				// Kept in the line as a link to the media
+				rewritten = strings.TrimSpace(r.htmlToMarkdown(tag))
+			case name == "br":
+				// A line break, as a markdown hard break
+				rewritten = "\\\n"
+			}
+			replacements = append(replacements, replacement{start, stop, rewritten})
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(replacements) == 0 {
+		return markdown
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, replacement := range replacements {
+		builder.WriteString(markdown[last:replacement.start])
+		builder.WriteString(replacement.rewritten)
+		last = replacement.stop
+	}
+	builder.WriteString(markdown[last:])
+	return builder.String()
+}
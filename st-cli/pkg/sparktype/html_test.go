@@ -0,0 +1,118 @@
+package sparktype
+
+import "testing"
+
+func TestRewriteHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		markdown    string
+		want        string // Converted to markdown, by default
+		wantAllowed string // With its formatting kept, WithHTML(true)
+	}{
+		{
+			name:        "figure",
+			markdown:    "<figure>\n<img src=\"cat.png\" alt=\"A cat\">\n<figcaption>The cat</figcaption>\n</figure>\n",
+			want:        "\n![A cat](cat.png)\n\nThe cat\n\n",
+			wantAllowed: "\n![A cat](cat.png)\n\nThe cat\n\n",
+		},
+		{
+			name:        "iframe",
+			markdown:    "<iframe src=\"https://video.example.com/1\"></iframe>\n",
+			want:        "\n[Embedded media](https://video.example.com/1)\n\n",
+			wantAllowed: "\n[Embedded media](https://video.example.com/1)\n\n",
+		},
+		{
+			name:        "iframe with a title and fallback text",
+			markdown:    "<iframe src=\"https://video.example.com/1\" title=\"Talk\">Your browser can't show this</iframe>\n",
+			want:        "\n[Talk](https://video.example.com/1)\n\n",
+			wantAllowed: "\n[Talk](https://video.example.com/1)\n\n",
+		},
+		{
+			name:        "inline video",
+			markdown:    "Watch <video src=\"clip.mp4\"></video> now.\n",
+			want:        "Watch [Embedded media](clip.mp4) now.\n",
+			wantAllowed: "Watch [Embedded media](clip.mp4) now.\n",
+		},
+		{
+			name:        "inline img",
+			markdown:    "Look: <img src=\"dog.png\" alt=\"A dog\"> here.\n",
+			want:        "Look: ![A dog](dog.png) here.\n",
+			wantAllowed: "Look: ![A dog](dog.png) here.\n",
+		},
+		{
+			name:        "br",
+			markdown:    "One<br>two<br/>three\n",
+			want:        "One\\\ntwo\\\nthree\n",
+			wantAllowed: "One\\\ntwo\\\nthree\n",
+		},
+		{
+			name:        "nested blocks",
+			markdown:    "<div>\n<section>\n<p>Inner <a href=\"/x\">link</a></p>\n<ul><li>a</li><li>b</li></ul>\n</section>\n</div>\n",
+			want:        "\nInner [link](/x)\n\n- a\n\n- b\n\n",
+			wantAllowed: "\nInner [link](/x)\n\n- a\n\n- b\n\n",
+		},
+		{
+			name:        "block in a list",
+			markdown:    "- item\n- <div>nested <b>block</b></div>\n",
+			want:        "- item\n- nested block\n",
+			wantAllowed: "- item\n- nested **block**\n",
+		},
+		{
+			name:        "inline formatting and link",
+			markdown:    "Press <kbd>q</kbd> to <b>quit</b>, or see <a href=\"/help\">the <em>help</em></a>.\n",
+			want:        "Press q to quit, or see [the help](/help).\n",
+			wantAllowed: "Press `q` to **quit**, or see [the *help*](/help).\n",
+		},
+		{
+			name:        "formatting in a block",
+			markdown:    "<p>Some <strong>bold</strong> and <del>old</del> text</p>\n",
+			want:        "\nSome bold and old text\n\n",
+			wantAllowed: "\nSome **bold** and ~~old~~ text\n\n",
+		},
+		{
+			name:        "anchor without a destination",
+			markdown:    "A <a name=\"top\">marker</a> here.\n",
+			want:        "A marker here.\n",
+			wantAllowed: "A marker here.\n",
+		},
+		{
+			name:        "no HTML",
+			markdown:    "Just *markdown*.\n",
+			want:        "Just *markdown*.\n",
+			wantAllowed: "Just *markdown*.\n",
+		},
+	}
+
+	for _, allowed := range []bool{false, true} {
+		renderer, err := NewContentRenderer(WithHTML(allowed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, test := range tests {
+			want := test.want
+			if allowed {
+				want = test.wantAllowed
+			}
+			if got := renderer.rewriteHTML(test.markdown); got != want {
+				t.Errorf("%s, HTML allowed %v: rewriteHTML() = %q, want %q", test.name, allowed, got, want)
+			}
+		}
+	}
+}
+
+func TestEmbeddedMediaLabelTranslated(t *testing.T) {
+	translate := func(s string) string {
+		if s == "Embedded media" {
+			return "Média intégré"
+		}
+		return s
+	}
+	renderer, err := NewContentRenderer(WithTranslator(translate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Watch [Média intégré](clip.mp4) now.\n"
+	if got := renderer.rewriteHTML("Watch <video src=\"clip.mp4\"></video> now.\n"); got != want {
+		t.Errorf("rewriteHTML() = %q, want %q", got, want)
+	}
+}
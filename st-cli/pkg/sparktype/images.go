@@ -0,0 +1,358 @@
+package sparktype
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ImageFetcher fetches the bytes of an image referenced by content,
+// abandoning the request when ctx is cancelled
+type ImageFetcher func(ctx context.Context, ref string) ([]byte, error)
+
+// graphicsProtocol is a terminal escape protocol for displaying images
+type graphicsProtocol int
+
+const (
+	graphicsNone graphicsProtocol = iota
+	graphicsKitty
+	graphicsITerm
+)
+
+// kittyChunkSize is the largest payload kitty accepts in one escape sequence
+const kittyChunkSize = 4096
+
+// maxImageRows caps the height of inline images, in terminal rows
+const maxImageRows = 30
+
+// detectGraphicsProtocol guesses the image protocol supported by the
+// terminal from its environment variables
+func detectGraphicsProtocol() graphicsProtocol {
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("TERM") == "xterm-kitty" {
+		return graphicsKitty
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm":
+		return graphicsITerm
+	case "ghostty":
+		return graphicsKitty
+	}
+	return graphicsNone
+}
+
+// WithInlineImages fetches images with fetch and displays them inline when
+// the terminal supports a graphics protocol. Placeholders are used otherwise.
+func WithInlineImages(fetch ImageFetcher) RendererOption {
+	return func(r *ContentRenderer) {
+		r.graphics = detectGraphicsProtocol()
+		r.fetchImage = fetch
+	}
+}
+
+// WithImagePreview fetches images with fetch and draws them as coloured
+// half-block characters when the terminal can't display them inline
+func WithImagePreview(fetch ImageFetcher) RendererOption {
+	return func(r *ContentRenderer) {
+		r.imagePreview = true
+		r.fetchImage = fetch
+	}
+}
+
+// imageCache remembers fetched images, so that rerendering a page doesn't
+// fetch its images again
+type imageCache struct {
+	mu     sync.Mutex
+	images map[string]cachedImage
+}
+
+// cachedImage is the result of fetching one image
+type cachedImage struct {
+	data []byte
+	err  error
+}
+
+// fetchesImages reports whether the renderer fetches images at all
+func (r *ContentRenderer) fetchesImages() bool {
+	if r.fetchImage == nil {
+		return false
+	}
+	return r.graphics != graphicsNone || r.imagePreview
+}
+
+// loadImage fetches an image, or returns it from the cache. A fetch cut
+// short by ctx isn't cached, so the image is fetched again next time.
+func (r *ContentRenderer) loadImage(ctx context.Context, ref string) ([]byte, error) {
+	r.imageCache.mu.Lock()
+	cached, ok := r.imageCache.images[ref]
+	r.imageCache.mu.Unlock()
+	if ok {
+		return cached.data, cached.err
+	}
+
+	data, err := r.fetchImage(ctx, ref)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	r.imageCache.mu.Lock()
+	if r.imageCache.images == nil {
+		r.imageCache.images = map[string]cachedImage{}
+	}
+	r.imageCache.images[ref] = cachedImage{data: data, err: err}
+	r.imageCache.mu.Unlock()
+	return data, err
+}
+
+// PrefetchImages fetches the images in the content ahead of rendering it,
+// so that rendering doesn't wait on the network. It is safe to call from a
+// background command, and stops fetching when ctx is cancelled.
+func (r *ContentRenderer) PrefetchImages(ctx context.Context, content *ContentFile) {
+	if content == nil || !r.fetchesImages() {
+		return
+	}
+
+	var refs []string
+	for _, img := range r.frontmatterImages(content) {
+		refs = append(refs, img.URL)
+	}
+	for _, match := range imageRegex.FindAllStringSubmatch(content.Content, -1) {
+		refs = append(refs, resolveURL(r.baseURL, match[2]))
+	}
+	for _, ref := range refs {
+		if ctx.Err() != nil {
+			return
+		}
+		r.loadImage(ctx, ref)
+	}
+}
+
+// inlineImages collects the escape sequences for images in a document. Each
+// image is rendered as a marker word, replaced after glamour has run.
+type inlineImages struct {
+	sequences map[string]string
+}
+
+// imageMarkdown returns the markdown to render for an image: a marker if the
+// image can be shown inline or previewed, or the placeholder text otherwise
+func (r *ContentRenderer) imageMarkdown(images *inlineImages, ref, placeholder string) string {
+	if !r.fetchesImages() {
+		return placeholder
+	}
+
+	// Rendering can't be abandoned, and usually finds the image prefetched
+	data, err := r.loadImage(context.Background(), ref)
+	if err != nil {
+		return placeholder
+	}
+
+	var sequence string
+	if r.graphics != graphicsNone {
+		sequence, err = encodeInlineImage(r.graphics, data, r.imageColumns())
+	} else {
+		sequence, err = halfBlockImage(data, r.imageColumns())
+	}
+	if err != nil {
+		return placeholder
+	}
+
+	// The marker goes in a paragraph of its own so that its whole line can
+	// be swapped for the image
+	marker := fmt.Sprintf("STCLIIMAGE%d", len(images.sequences))
+	images.sequences[marker] = sequence
+	return "\n\n" + marker + "\n\n"
+}
+
+// replaceMarkers swaps the image markers in rendered output for their
+// escape sequences
+func (images *inlineImages) replaceMarkers(rendered string) string {
+	if len(images.sequences) == 0 {
+		return rendered
+	}
+
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		for marker, sequence := range images.sequences {
+			if strings.Contains(line, marker) {
+				lines[i] = sequence
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// imageColumns returns the width available for an inline image
+func (r *ContentRenderer) imageColumns() int {
+	if r.wordWrap <= 0 {
+		return 80
+	}
+	// Leave room for glamour's document margins
+	if r.wordWrap > 8 {
+		return r.wordWrap - 4
+	}
+	return r.wordWrap
+}
+
+// encodeInlineImage returns the escape sequence that displays an image at
+// most columns wide, keeping its aspect ratio
+func encodeInlineImage(protocol graphicsProtocol, data []byte, columns int) (string, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("unsupported image: %v", err)
+	}
+
+	// Terminal cells are roughly twice as tall as they are wide, and about
+	// 8 pixels across; small images aren't scaled up
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", fmt.Errorf("empty image")
+	}
+	if natural := (bounds.Dx() + 7) / 8; natural < columns {
+		columns = natural
+	}
+	rows := columns * bounds.Dy() / bounds.Dx() / 2
+	if rows > maxImageRows {
+		columns = columns * maxImageRows / rows
+		rows = maxImageRows
+	}
+	if columns < 1 {
+		columns = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	switch protocol {
+	case graphicsKitty:
+		// Kitty only accepts PNG, so re-encode everything else
+		if format != "png" {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return "", err
+			}
+			data = buf.Bytes()
+		}
+		return kittySequence(data, columns, rows), nil
+
+	case graphicsITerm:
+		return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+			len(data), columns, rows, base64.StdEncoding.EncodeToString(data)), nil
+	}
+
+	return "", fmt.Errorf("no graphics protocol")
+}
+
+// halfBlockImage draws an image at most columns wide using "▀" characters,
+// each showing two pixels: the top one in the foreground colour and the
+// bottom one in the background colour
+func halfBlockImage(data []byte, columns int) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("unsupported image: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", fmt.Errorf("empty image")
+	}
+	if bounds.Dx() < columns {
+		columns = bounds.Dx()
+	}
+	// Each half of a cell is roughly square, so keep the pixel aspect ratio
+	height := columns * bounds.Dy() / bounds.Dx()
+	if height > 2*maxImageRows {
+		columns = columns * 2 * maxImageRows / height
+		height = 2 * maxImageRows
+	}
+	if columns < 1 {
+		columns = 1
+	}
+	if height < 2 {
+		height = 2
+	}
+
+	var lines []string
+	for y := 0; y < height; y += 2 {
+		var line strings.Builder
+		line.WriteString("  ")
+		for x := 0; x < columns; x++ {
+			top := averageColor(img, x, y, columns, height)
+			bottom := averageColor(img, x, y+1, columns, height)
+			line.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color(top)).
+				Background(lipgloss.Color(bottom)).
+				Render("▀"))
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// averageColor returns the average colour, as a hex string, of the region
+// of img covered by cell (x, y) when it is scaled to columns by rows
+func averageColor(img image.Image, x, y, columns, rows int) string {
+	bounds := img.Bounds()
+	if y >= rows {
+		y = rows - 1
+	}
+
+	x0 := bounds.Min.X + x*bounds.Dx()/columns
+	x1 := bounds.Min.X + (x+1)*bounds.Dx()/columns
+	y0 := bounds.Min.Y + y*bounds.Dy()/rows
+	y1 := bounds.Min.Y + (y+1)*bounds.Dy()/rows
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+
+	var red, green, blue, count uint64
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			c := color.RGBAModel.Convert(img.At(px, py)).(color.RGBA)
+			red += uint64(c.R)
+			green += uint64(c.G)
+			blue += uint64(c.B)
+			count++
+		}
+	}
+	return fmt.Sprintf("#%02x%02x%02x", red/count, green/count, blue/count)
+}
+
+// kittySequence encodes PNG data using the kitty graphics protocol, split
+// into chunks the terminal will accept
+func kittySequence(data []byte, columns, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var builder strings.Builder
+	for start := 0; start < len(payload); start += kittyChunkSize {
+		end := start + kittyChunkSize
+		more := 1
+		if end >= len(payload) {
+			end = len(payload)
+			more = 0
+		}
+
+		if start == 0 {
+			// q=2 stops the terminal from writing responses to stdin
+			builder.WriteString(fmt.Sprintf("\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;", columns, rows, more))
+		} else {
+			builder.WriteString(fmt.Sprintf("\x1b_Gm=%d;", more))
+		}
+		builder.WriteString(payload[start:end])
+		builder.WriteString("\x1b\\")
+	}
+	return builder.String()
+}
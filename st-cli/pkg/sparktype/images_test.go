@@ -0,0 +1,38 @@
+package sparktype
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPrefetchImagesStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var fetched []string
+	fetch := func(ctx context.Context, ref string) ([]byte, error) {
+		fetched = append(fetched, ref)
+		// The load is abandoned while the first image is on its way
+		cancel()
+		return nil, ctx.Err()
+	}
+	renderer, err := NewContentRenderer(WithBaseURL("https://example.com"), WithImagePreview(fetch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := &ContentFile{Content: "![One](/one.png)\n\n![Two](/two.png)\n"}
+
+	renderer.PrefetchImages(ctx, content)
+	if len(fetched) != 1 {
+		t.Errorf("fetched %v after the load was cancelled, want only the first image", fetched)
+	}
+
+	// The cancelled fetch isn't remembered as a failure
+	renderer.fetchImage = func(ctx context.Context, ref string) ([]byte, error) {
+		fetched = append(fetched, ref)
+		return nil, errors.New("not an image")
+	}
+	renderer.PrefetchImages(context.Background(), content)
+	if len(fetched) != 3 {
+		t.Errorf("fetched %v, want the first image again and then the second", fetched)
+	}
+}
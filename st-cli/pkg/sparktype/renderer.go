@@ -0,0 +1,605 @@
+package sparktype
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/styles"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// wordsPerMinute is the reading speed used to estimate reading time
+const wordsPerMinute = 200
+
+// ContentRenderer handles rendering markdown content for terminal display
+type ContentRenderer struct {
+	glamour        goldmark.Markdown
+	term           *glamour.TermRenderer
+	style          string
+	styleFile      string
+	codeTheme      string
+	wordWrap       int
+	baseURL        string
+	graphics       graphicsProtocol
+	imagePreview   bool
+	fetchImage     ImageFetcher
+	imageCache     imageCache
+	tocMinHeadings int
+	emoji          bool
+	footnotes      bool
+	typographer    bool
+	allowHTML      bool
+	translate      func(string) string
+}
+
+// RendererOption configures a ContentRenderer
+type RendererOption func(*ContentRenderer)
+
+// WithTOC prepends a table of contents to pages with at least minHeadings
+// headings. A value of 0 disables the inline table of contents.
+func WithTOC(minHeadings int) RendererOption {
+	return func(r *ContentRenderer) {
+		r.tocMinHeadings = minHeadings
+	}
+}
+
+// WithStyle selects one of glamour's standard styles, such as "dark",
+// "light" or "notty". The default, "auto", follows the terminal background.
+func WithStyle(style string) RendererOption {
+	return func(r *ContentRenderer) {
+		r.style = style
+	}
+}
+
+// WithStyleFile loads the style from a glamour JSON style file instead of
+// using a standard style
+func WithStyleFile(path string) RendererOption {
+	return func(r *ContentRenderer) {
+		r.styleFile = path
+	}
+}
+
+// WithCodeTheme highlights code blocks with the named chroma style instead
+// of the colours from the glamour style. An unknown name is an error from
+// NewContentRenderer.
+func WithCodeTheme(theme string) RendererOption {
+	return func(r *ContentRenderer) {
+		r.codeTheme = theme
+	}
+}
+
+// WithWordWrap sets the column at which rendered content wraps; 0 disables
+// wrapping
+func WithWordWrap(width int) RendererOption {
+	return func(r *ContentRenderer) {
+		r.wordWrap = width
+	}
+}
+
+// WithTranslator translates the text the renderer adds to pages, such as
+// "Published: %s", and the month names in dates. By default it is left in
+// English.
+func WithTranslator(translate func(string) string) RendererOption {
+	return func(r *ContentRenderer) {
+		r.translate = translate
+	}
+}
+
+// WithBaseURL resolves relative image URLs against the site's base URL
+func WithBaseURL(baseURL string) RendererOption {
+	return func(r *ContentRenderer) {
+		r.baseURL = baseURL
+	}
+}
+
+// NewContentRenderer creates a new content renderer
+func NewContentRenderer(opts ...RendererOption) (*ContentRenderer, error) {
+	renderer := &ContentRenderer{
+		style:     "auto",
+		wordWrap:  100,
+		translate: func(s string) string { return s },
+	}
+	for _, opt := range opts {
+		opt(renderer)
+	}
+
+	termRenderer, err := renderer.newTermRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup goldmark for markdown parsing
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.DefinitionList),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithHardWraps(),
+		),
+	)
+
+	renderer.glamour = md
+	renderer.term = termRenderer
+
+	return renderer, nil
+}
+
+// newTermRenderer creates the glamour renderer for the current settings
+func (r *ContentRenderer) newTermRenderer() (*glamour.TermRenderer, error) {
+	style, err := r.styleConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts := []glamour.TermRendererOption{
+		glamour.WithStyles(style),
+		glamour.WithWordWrap(r.wordWrap),
+	}
+	if r.emoji {
+		opts = append(opts, glamour.WithEmoji())
+	}
+	return glamour.NewTermRenderer(opts...)
+}
+
+// styleConfig returns the glamour style for the current settings
+func (r *ContentRenderer) styleConfig() (ansi.StyleConfig, error) {
+	var style ansi.StyleConfig
+	switch {
+	case r.styleFile != "":
+		data, err := os.ReadFile(r.styleFile)
+		if err != nil {
+			return style, err
+		}
+		if err := json.Unmarshal(data, &style); err != nil {
+			return style, fmt.Errorf("%s: %v", r.styleFile, err)
+		}
+	case r.style == "auto":
+		style = glamour.LightStyleConfig
+		if lipgloss.HasDarkBackground() {
+			style = glamour.DarkStyleConfig
+		}
+	default:
+		standard, ok := glamour.DefaultStyles[r.style]
+		if !ok {
+			return style, fmt.Errorf("%s: style not found", r.style)
+		}
+		style = *standard
+	}
+
+	// Task list checkboxes are drawn as symbols rather than glamour's
+	// bracketed marks, unless a style file chooses its own
+	if r.styleFile == "" || style.Task.Ticked == "" {
+		style.Task.Ticked = "☑ "
+	}
+	if r.styleFile == "" || style.Task.Unticked == "" {
+		style.Task.Unticked = "☐ "
+	}
+
+	// The style's own code colours take precedence over a chroma theme, so
+	// they're dropped when one is chosen
+	if r.codeTheme != "" {
+		if _, ok := styles.Registry[r.codeTheme]; !ok {
+			return style, fmt.Errorf("%s: code theme not found", r.codeTheme)
+		}
+		style.CodeBlock.Theme = r.codeTheme
+		style.CodeBlock.Chroma = nil
+	}
+	return style, nil
+}
+
+// SetWordWrap changes the wrap width, rebuilding the glamour renderer if it
+// differs from the current one
+func (r *ContentRenderer) SetWordWrap(width int) error {
+	if width == r.wordWrap && r.term != nil {
+		return nil
+	}
+
+	previous := r.wordWrap
+	r.wordWrap = width
+	term, err := r.newTermRenderer()
+	if err != nil {
+		r.wordWrap = previous
+		return err
+	}
+	r.term = term
+	return nil
+}
+
+// RenderContent renders markdown content for terminal display
+func (r *ContentRenderer) RenderContent(content *ContentFile) (string, error) {
+	if content == nil {
+		return "", nil
+	}
+
+	// Build full content with title and metadata
+	var builder strings.Builder
+	images := &inlineImages{sequences: map[string]string{}}
+
+	// Add title
+	if content.Title != "" {
+		builder.WriteString("# ")
+		builder.WriteString(content.Title)
+		builder.WriteString("\n\n")
+	}
+
+	if content.Author != "" {
+		builder.WriteString(fmt.Sprintf(r.translate("By %s"), content.Author))
+		builder.WriteString("\n\n")
+	}
+
+	// Add metadata if available
+	var meta []string
+	if !content.Date.IsZero() {
+		meta = append(meta, fmt.Sprintf(r.translate("Published: %s"), FormatDate(content.Date, "January 2, 2006", r.translate)))
+	}
+	if stats := r.readingStats(content.Content); stats != "" {
+		meta = append(meta, stats)
+	}
+	if len(meta) > 0 {
+		builder.WriteString("*")
+		builder.WriteString(strings.Join(meta, " · "))
+		builder.WriteString("*\n\n")
+	}
+
+	if content.Description != "" {
+		builder.WriteString("*")
+		builder.WriteString(content.Description)
+		builder.WriteString("*\n\n")
+	}
+
+	// Add frontmatter images
+	frontmatterImages := r.frontmatterImages(content)
+	for _, img := range frontmatterImages {
+		var placeholder strings.Builder
+		placeholder.WriteString("📷 **[BANNER IMAGE]**")
+		if img.AltText != "" {
+			placeholder.WriteString(fmt.Sprintf(" %s", img.AltText))
+		}
+		if img.Width > 0 && img.Height > 0 {
+			placeholder.WriteString(fmt.Sprintf(" (%dx%d)", img.Width, img.Height))
+		}
+		placeholder.WriteString(fmt.Sprintf("\n   *Source: %s*", img.URL))
+		placeholder.WriteString("\n   *Images cannot be displayed in terminal*")
+		builder.WriteString(r.imageMarkdown(images, img.URL, placeholder.String()))
+		builder.WriteString("\n\n")
+	}
+
+	// Add horizontal rule before content
+	if content.Title != "" || len(meta) > 0 || content.Description != "" || len(frontmatterImages) > 0 {
+		builder.WriteString("---\n\n")
+	}
+
+	// Add table of contents for long pages
+	if r.wantsTOC(content) {
+		builder.WriteString(r.buildTOC(content.Content))
+	}
+
+	// Process content to handle images, after laying out tables that
+	// wouldn't fit
+	processedContent := r.processImages(images, r.narrowTables(r.applyExtensions(content.Content)))
+	builder.WriteString(processedContent)
+
+	// Render using glamour for terminal display
+	rendered, err := r.term.Render(builder.String())
+	if err != nil {
+		// Fallback to plain text if glamour fails
+		return images.replaceMarkers(builder.String()), nil
+	}
+
+	return images.replaceMarkers(rendered), nil
+}
+
+// RenderMarkdown renders plain markdown text using glamour
+func (r *ContentRenderer) RenderMarkdown(markdown string) (string, error) {
+	if r.term == nil {
+		return markdown, nil
+	}
+
+	rendered, err := r.term.Render(markdown)
+	if err != nil {
+		return markdown, nil
+	}
+
+	return rendered, nil
+}
+
+// Heading represents a heading extracted from markdown content
+type Heading struct {
+	Level int
+	Text  string
+	ID    string
+}
+
+// ExtractHeadings returns the headings in the markdown, in document order
+func (r *ContentRenderer) ExtractHeadings(markdown string) []Heading {
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	var headings []Heading
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		h := Heading{
+			Level: heading.Level,
+			Text:  string(heading.Text(source)),
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if idBytes, ok := id.([]byte); ok {
+				h.ID = string(idBytes)
+			}
+		}
+		headings = append(headings, h)
+		return ast.WalkSkipChildren, nil
+	})
+
+	return headings
+}
+
+// Link represents a link extracted from markdown content
+type Link struct {
+	Text        string
+	Destination string
+}
+
+// ExtractLinks returns the links in the markdown, in document order. Links
+// to headings on the same page have destinations starting with "#", followed
+// by the heading's ID as ExtractHeadings reports it.
+func (r *ContentRenderer) ExtractLinks(markdown string) []Link {
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	var links []Link
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var link Link
+		switch node := n.(type) {
+		case *ast.Link:
+			link = Link{Text: string(node.Text(source)), Destination: string(node.Destination)}
+		case *ast.AutoLink:
+			link = Link{Text: string(node.Label(source)), Destination: string(node.URL(source))}
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		if link.Destination == "" || link.Destination == "#" {
+			return ast.WalkSkipChildren, nil
+		}
+		if link.Text == "" {
+			link.Text = link.Destination
+		}
+		links = append(links, link)
+		return ast.WalkSkipChildren, nil
+	})
+
+	return links
+}
+
+// wantsTOC reports whether a table of contents should be prepended to the content
+func (r *ContentRenderer) wantsTOC(content *ContentFile) bool {
+	// Frontmatter can force the table of contents on or off per page
+	if toc, ok := content.Metadata["toc"].(bool); ok {
+		if !toc {
+			return false
+		}
+		return len(r.ExtractHeadings(content.Content)) > 0
+	}
+
+	if r.tocMinHeadings <= 0 {
+		return false
+	}
+	return len(r.ExtractHeadings(content.Content)) >= r.tocMinHeadings
+}
+
+// buildTOC builds a markdown table of contents linking to each heading
+func (r *ContentRenderer) buildTOC(markdown string) string {
+	headings := r.ExtractHeadings(markdown)
+	if len(headings) == 0 {
+		return ""
+	}
+
+	// Indent relative to the shallowest heading on the page
+	minLevel := headings[0].Level
+	for _, h := range headings {
+		if h.Level < minLevel {
+			minLevel = h.Level
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("**Contents**\n\n")
+	for _, h := range headings {
+		builder.WriteString(strings.Repeat("  ", h.Level-minLevel))
+		builder.WriteString(fmt.Sprintf("- [%s](#%s)\n", h.Text, h.ID))
+	}
+	builder.WriteString("\n---\n\n")
+
+	return builder.String()
+}
+
+// StripMarkdown removes markdown formatting and returns plain text
+func (r *ContentRenderer) StripMarkdown(markdown string) string {
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	var builder strings.Builder
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			// End each block on a line of its own, keeping table rows together
+			if n.Kind() == extast.KindTableCell {
+				builder.WriteString(" ")
+			} else if n.Type() == ast.TypeBlock && !strings.HasSuffix(builder.String(), "\n") {
+				builder.WriteString("\n")
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Text:
+			builder.Write(plainText(node.Segment.Value(source)))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				builder.WriteString("\n")
+			}
+		case *ast.String:
+			builder.Write(plainText(node.Value))
+		case *ast.AutoLink:
+			builder.Write(node.URL(source))
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				builder.Write(line.Value(source))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(builder.String())
+}
+
+// FormatDate formats t with layout, after translating the layout and then
+// the month names in the result
+func FormatDate(t time.Time, layout string, translate func(string) string) string {
+	layout = translate(layout)
+	// Month names are put in after formatting, so the translations can't be
+	// mistaken for parts of the layout
+	layout = strings.ReplaceAll(layout, "January", "\x01")
+	layout = strings.ReplaceAll(layout, "Jan", "\x02")
+	formatted := t.Format(layout)
+	formatted = strings.ReplaceAll(formatted, "\x01", translate(t.Month().String()))
+	return strings.ReplaceAll(formatted, "\x02", translate(t.Month().String()[:3]))
+}
+
+// readingStats returns the word count and estimated reading time of the
+// markdown, like "5 min read · 940 words", or "" if it has no words
+func (r *ContentRenderer) readingStats(markdown string) string {
+	words := r.WordCount(markdown)
+	if words == 0 {
+		return ""
+	}
+
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	if words == 1 {
+		return fmt.Sprintf(r.translate("%d min read · 1 word"), minutes)
+	}
+	return fmt.Sprintf(r.translate("%d min read · %d words"), minutes, words)
+}
+
+// WordCount returns the number of words in the text of the markdown
+func (r *ContentRenderer) WordCount(markdown string) int {
+	return len(strings.Fields(r.StripMarkdown(markdown)))
+}
+
+// plainText decodes the escapes and entities in a markdown text segment
+func plainText(value []byte) []byte {
+	value = util.UnescapePunctuations(value)
+	value = util.ResolveNumericReferences(value)
+	return util.ResolveEntityNames(value)
+}
+
+// imageRegex matches markdown images: ![alt text](image_url "optional title")
+var imageRegex = regexp.MustCompile(`!\[([^\]]*)\]\(([^)]+)(?:\s+"([^"]*)")?\)`)
+
+// processImages converts image markdown to terminal-friendly text representations
+func (r *ContentRenderer) processImages(images *inlineImages, content string) string {
+	return imageRegex.ReplaceAllStringFunc(content, func(match string) string {
+		submatches := imageRegex.FindStringSubmatch(match)
+		if len(submatches) < 3 {
+			return match // Return original if parsing fails
+		}
+
+		altText := submatches[1]
+		imageURL := resolveURL(r.baseURL, submatches[2])
+		title := ""
+		if len(submatches) > 3 && submatches[3] != "" {
+			title = submatches[3]
+		}
+
+		// Create terminal representation
+		var representation strings.Builder
+		representation.WriteString("📷 **[IMAGE]**")
+
+		if altText != "" {
+			representation.WriteString(fmt.Sprintf(" %s", altText))
+		}
+
+		if title != "" && title != altText {
+			representation.WriteString(fmt.Sprintf(" - %s", title))
+		}
+
+		// Add image path/URL info
+		representation.WriteString(fmt.Sprintf("\n   *Source: %s*", imageURL))
+
+		// Add helpful note
+		representation.WriteString("\n   *Images cannot be displayed in terminal*")
+
+		return r.imageMarkdown(images, imageURL, representation.String())
+	})
+}
+
+// ImageInfo represents extracted image metadata
+type ImageInfo struct {
+	AltText string
+	URL     string
+	Title   string
+	Width   int
+	Height  int
+}
+
+// frontmatterImages returns the frontmatter images of the content, with
+// their URLs resolved against the site
+func (r *ContentRenderer) frontmatterImages(content *ContentFile) []ImageInfo {
+	images := ExtractImageInfo(content.Metadata)
+	for i := range images {
+		images[i].URL = resolveURL(r.baseURL, images[i].URL)
+	}
+	return images
+}
+
+// ExtractImageInfo extracts metadata from SparkType image frontmatter
+func ExtractImageInfo(metadata map[string]interface{}) []ImageInfo {
+	var images []ImageInfo
+
+	// Check for banner_image
+	if bannerImage, ok := metadata["banner_image"].(map[string]interface{}); ok {
+		var info ImageInfo
+		if alt, ok := bannerImage["alt"].(string); ok {
+			info.AltText = alt
+		}
+		if src, ok := bannerImage["src"].(string); ok {
+			info.URL = src
+		}
+		if width, ok := bannerImage["width"].(float64); ok {
+			info.Width = int(width)
+		}
+		if height, ok := bannerImage["height"].(float64); ok {
+			info.Height = int(height)
+		}
+		images = append(images, info)
+	}
+
+	return images
+}
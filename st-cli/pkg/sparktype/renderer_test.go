@@ -0,0 +1,195 @@
+package sparktype
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// ansiEscape matches the styling glamour puts in rendered output
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "headings",
+			markdown: "# Title\n\n## Sub *heading*\n\nText.",
+			want:     "Title\nSub heading\nText.",
+		},
+		{
+			name:     "nested lists",
+			markdown: "- one\n- two\n  - nested **bold**\n  - nested two\n- three\n\n1. first\n   1. inner",
+			want:     "one\ntwo\nnested bold\nnested two\nthree\nfirst\ninner",
+		},
+		{
+			name:     "links and images",
+			markdown: "See [the docs](https://example.com/docs) and ![a cat](cat.png). <https://auto.example.com>",
+			want:     "See the docs and a cat. https://auto.example.com",
+		},
+		{
+			name:     "inline code",
+			markdown: "Run `go test ./...` now.",
+			want:     "Run go test ./... now.",
+		},
+		{
+			name:     "fenced and indented code",
+			markdown: "Before.\n\n```go\nfunc main() {\n\t*x = 1\n}\n```\n\n    indented **code**",
+			want:     "Before.\nfunc main() {\n\t*x = 1\n}\nindented **code**",
+		},
+		{
+			name:     "emphasis",
+			markdown: "*em* _also_ **strong** __strong too__ ~~gone~~ ***both***",
+			want:     "em also strong strong too gone both",
+		},
+		{
+			name:     "HTML entities",
+			markdown: "Fish &amp; chips &lt;3 &copy; &#8212; &#x2713;",
+			want:     "Fish & chips <3 © — ✓",
+		},
+		{
+			name:     "HTML",
+			markdown: "Text <span>inline</span> end.\n\n<div>block</div>",
+			want:     "Text inline end.",
+		},
+		{
+			name:     "table",
+			markdown: "| a | b |\n|---|---|\n| 1 | 2 |",
+			want:     "a b \n1 2",
+		},
+	}
+
+	renderer, err := NewContentRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := renderer.StripMarkdown(test.markdown); got != test.want {
+				t.Errorf("StripMarkdown(%q) = %q, want %q", test.markdown, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTaskListGlyphs(t *testing.T) {
+	const markdown = "- [x] Write the tests\n- [ ] Ship it\n- [X] Review\n- Plain item\n"
+
+	dir := t.TempDir()
+	ownGlyphs := filepath.Join(dir, "own.json")
+	if err := os.WriteFile(ownGlyphs, []byte(`{"task": {"ticked": "[done] ", "unticked": "[todo] "}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noGlyphs := filepath.Join(dir, "none.json")
+	if err := os.WriteFile(noGlyphs, []byte(`{"document": {"margin": 1}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		opts             []RendererOption
+		ticked, unticked string
+	}{
+		{name: "plain style", opts: []RendererOption{WithStyle("notty")}, ticked: "☑", unticked: "☐"},
+		{name: "dark style", opts: []RendererOption{WithStyle("dark")}, ticked: "☑", unticked: "☐"},
+		{name: "style file with its own marks", opts: []RendererOption{WithStyleFile(ownGlyphs)}, ticked: "[done]", unticked: "[todo]"},
+		{name: "style file without marks", opts: []RendererOption{WithStyleFile(noGlyphs)}, ticked: "☑", unticked: "☐"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			renderer, err := NewContentRenderer(test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rendered, err := renderer.RenderMarkdown(markdown)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := map[string]string{
+				"Write the tests": test.ticked,
+				"Ship it":         test.unticked,
+				"Review":          test.ticked,
+			}
+			for _, line := range strings.Split(ansiEscape.ReplaceAllString(rendered, ""), "\n") {
+				line = strings.TrimSpace(line)
+				for item, mark := range want {
+					if strings.HasSuffix(line, item) {
+						if got := strings.TrimSpace(strings.TrimSuffix(line, item)); got != mark {
+							t.Errorf("%q is marked %q, want %q", item, got, mark)
+						}
+						delete(want, item)
+					}
+				}
+				if strings.HasSuffix(line, "Plain item") && (strings.Contains(line, test.ticked) || strings.Contains(line, test.unticked)) {
+					t.Errorf("plain item %q has a checkbox", line)
+				}
+			}
+			for item := range want {
+				t.Errorf("%q not found in %q", item, rendered)
+			}
+		})
+	}
+}
+
+func TestCodeTheme(t *testing.T) {
+	renderer, err := NewContentRenderer(WithStyle("dark"), WithCodeTheme("monokai"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	style, err := renderer.styleConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if style.CodeBlock.Theme != "monokai" || style.CodeBlock.Chroma != nil {
+		t.Errorf("code block theme %q, chroma %v; want monokai without the style's own colours", style.CodeBlock.Theme, style.CodeBlock.Chroma)
+	}
+
+	tests := []struct {
+		name, fence, code string
+		highlighted       bool
+	}{
+		{name: "go", fence: "go", code: "func main() {}", highlighted: true},
+		{name: "json", fence: "json", code: `{"a": 1}`, highlighted: true},
+		{name: "shell", fence: "sh", code: `echo "hi" # greet`, highlighted: true},
+		{name: "unknown language", fence: "nosuchlang", code: "foo bar"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := renderer.RenderMarkdown("```" + test.fence + "\n" + test.code + "\n```\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plain := strings.TrimSpace(ansiEscape.ReplaceAllString(rendered, "")); plain != test.code {
+				t.Errorf("code rendered as %q, want %q", plain, test.code)
+			}
+
+			// Every token of plain text gets the same colour, so a
+			// highlighted line has more than one
+			colours := map[string]bool{}
+			for _, line := range strings.Split(rendered, "\n") {
+				if !strings.Contains(ansiEscape.ReplaceAllString(line, ""), test.code) {
+					continue
+				}
+				for _, escape := range ansiEscape.FindAllString(line, -1) {
+					if escape != "\x1b[0m" {
+						colours[escape] = true
+					}
+				}
+			}
+			if highlighted := len(colours) > 2; highlighted != test.highlighted {
+				t.Errorf("%d colours on the code line, highlighted = %v, want %v", len(colours), highlighted, test.highlighted)
+			}
+		})
+	}
+
+	if _, err := NewContentRenderer(WithStyle("dark"), WithCodeTheme("nope")); err == nil {
+		t.Error("an unknown code theme was accepted")
+	}
+}
@@ -0,0 +1,155 @@
+package sparktype
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// tableMargin is the room glamour leaves around a table: the document
+// margins on either side
+const tableMargin = 4
+
+// tableSpan is a table's place in the markdown source, with its cells
+type tableSpan struct {
+	start, stop int
+	header      []string
+	rows        [][]string
+	width       int // Columns needed to draw the table
+}
+
+// narrowTables rewrites the tables too wide for the wrap width as a list
+// with an entry per row, each cell labelled with its column's header.
+// Tables that fit, or any table when wrapping is off, are left alone.
+func (r *ContentRenderer) narrowTables(markdown string) string {
+	if r.wordWrap <= 0 {
+		return markdown
+	}
+
+	source := []byte(markdown)
+	doc := r.glamour.Parser().Parse(text.NewReader(source))
+
+	var spans []tableSpan
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		table, ok := n.(*extast.Table)
+		if !ok {
+			continue
+		}
+		if span, ok := measureTable(table, source); ok && span.width > r.wordWrap {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return markdown
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, span := range spans {
+		builder.WriteString(markdown[last:span.start])
+		builder.WriteString(tableList(span))
+		last = span.stop
+	}
+	builder.WriteString(markdown[last:])
+	return builder.String()
+}
+
+// measureTable finds the source lines of a table, its cells and the width
+// it needs. It reports false for a table without cells to place it by.
+func measureTable(table *extast.Table, source []byte) (tableSpan, bool) {
+	span := tableSpan{start: -1}
+	var widths []int
+
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		column := 0
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			value := ""
+			if lines := cell.Lines(); lines.Len() > 0 {
+				segment := lines.At(0)
+				if span.start < 0 || segment.Start < span.start {
+					span.start = segment.Start
+				}
+				if segment.Stop > span.stop {
+					span.stop = segment.Stop
+				}
+				// A pipe is only escaped to keep it inside its cell
+				value = strings.TrimSpace(strings.ReplaceAll(string(segment.Value(source)), `\|`, "|"))
+			}
+			cells = append(cells, value)
+
+			if column == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := lipgloss.Width(string(cell.Text(source))); w > widths[column] {
+				widths[column] = w
+			}
+			column++
+		}
+
+		if row.Kind() == extast.KindTableHeader {
+			span.header = cells
+		} else {
+			span.rows = append(span.rows, cells)
+		}
+	}
+	if span.start < 0 {
+		return span, false
+	}
+
+	// Take in the whole of the first and last lines, pipes and all
+	for span.start > 0 && source[span.start-1] != '\n' {
+		span.start--
+	}
+	for span.stop < len(source) && source[span.stop] != '\n' {
+		span.stop++
+	}
+
+	// Each column is padded by a space either side and divided by a bar
+	span.width = tableMargin
+	for _, w := range widths {
+		span.width += w + 3
+	}
+	span.width -= 3
+	return span, true
+}
+
+// tableList renders a table as a list: each row's first cell becomes the
+// item, with the rest of the row beneath it as "header: value" pairs
+func tableList(span tableSpan) string {
+	header := func(i int) string {
+		if i < len(span.header) {
+			return span.header[i]
+		}
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, row := range span.rows {
+		if len(row) == 0 {
+			continue
+		}
+		builder.WriteString("- ")
+		builder.WriteString(labelled(header(0), row[0]))
+		builder.WriteString("\n")
+		for i, cell := range row[1:] {
+			if cell == "" {
+				continue
+			}
+			builder.WriteString("  - ")
+			builder.WriteString(labelled(header(i+1), cell))
+			builder.WriteString("\n")
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// labelled prefixes a cell with its column's header, in bold
+func labelled(header, cell string) string {
+	if header == "" {
+		return cell
+	}
+	return "**" + header + ":** " + cell
+}
@@ -0,0 +1,225 @@
+package sparktype
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SiteManifest represents the SparkType site manifest structure
+type SiteManifest struct {
+	SiteID           string           `json:"siteId" yaml:"siteId"`
+	GeneratorVersion string           `json:"generatorVersion" yaml:"generatorVersion"`
+	Title            string           `json:"title" yaml:"title"`
+	Description      string           `json:"description" yaml:"description"`
+	Theme            ThemeConfig      `json:"theme" yaml:"theme"`
+	Structure        []MenuItem       `json:"structure" yaml:"structure"`
+	CollectionItems  []CollectionItem `json:"collectionItems" yaml:"collectionItems"`
+	Collections      []Collection     `json:"collections" yaml:"collections"`
+}
+
+// ManifestError reports a manifest that parsed but lacks what every
+// SparkType site has, which usually means the URL isn't a SparkType site
+type ManifestError struct {
+	Problems []string
+}
+
+// Error describes the missing fields
+func (e *ManifestError) Error() string {
+	return "this doesn't look like a SparkType site: " + strings.Join(e.Problems, ", ")
+}
+
+// Errors the client's failures can be told apart by with errors.Is
+var (
+	// ErrNotFound is a page or manifest the site doesn't have
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is a request the site refused for want of credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrParse is a manifest or page that couldn't be parsed
+	ErrParse = errors.New("cannot parse")
+	// ErrNetwork is a request that never got a response from the site
+	ErrNetwork = errors.New("network error")
+)
+
+// kindError marks an error as one of the kinds above, keeping its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+// markError marks err as being of kind, so that errors.Is(err, kind) holds
+func markError(kind, err error) error {
+	return &kindError{kind: kind, err: err}
+}
+
+// Error returns the message of the marked error
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the kind and the marked error, for errors.Is and errors.As
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// StatusError reports a response from the site with a status other than
+// 200 OK, such as a page the manifest lists but the site doesn't serve
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+// Error describes the status, as in "HTTP 404: 404 Not Found"
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Code, e.Status)
+}
+
+// Is reports a 404 or 410 as ErrNotFound, and a 401 or 403 as
+// ErrUnauthorized
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound || e.Code == http.StatusGone
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized || e.Code == http.StatusForbidden
+	}
+	return false
+}
+
+// Validate checks that the manifest has a site ID, a title, and some pages
+// or collections
+func (m *SiteManifest) Validate() *ManifestError {
+	var problems []string
+	if m.SiteID == "" {
+		problems = append(problems, "the manifest has no siteId")
+	}
+	if m.Title == "" {
+		problems = append(problems, "the manifest has no title")
+	}
+	if len(m.Structure) == 0 && len(m.Collections) == 0 {
+		problems = append(problems, "the manifest lists no pages or collections")
+	}
+	if len(problems) > 0 {
+		return &ManifestError{Problems: problems}
+	}
+	return nil
+}
+
+// ContentRef identifies a piece of content listed in the manifest
+type ContentRef struct {
+	Title string
+	Path  string
+}
+
+// AllContent lists every page, at any depth, and every collection item in
+// the manifest, once each
+func (m *SiteManifest) AllContent() []ContentRef {
+	var refs []ContentRef
+	seen := make(map[string]bool)
+
+	var addPages func(items []MenuItem)
+	addPages = func(items []MenuItem) {
+		for _, item := range items {
+			if item.Path != "" && !seen[item.Path] {
+				seen[item.Path] = true
+				refs = append(refs, ContentRef{Title: item.Title, Path: item.Path})
+			}
+			addPages(item.Children)
+		}
+	}
+	addPages(m.Structure)
+
+	for _, item := range m.CollectionItems {
+		if item.Path != "" && !seen[item.Path] {
+			seen[item.Path] = true
+			refs = append(refs, ContentRef{Title: item.Title, Path: item.Path})
+		}
+	}
+
+	return refs
+}
+
+// CollectionForPage returns the collection listed by the page at path. A
+// page lists the collection stored beneath it: content/blog.md or
+// content/blog/index.md lists the collection in content/blog/.
+func (m *SiteManifest) CollectionForPage(path string) (Collection, bool) {
+	dir := strings.TrimSuffix(path, ".md")
+	dir = strings.TrimSuffix(dir, "/index")
+	for _, collection := range m.Collections {
+		if strings.TrimSuffix(collection.ContentPath, "/") == dir {
+			return collection, true
+		}
+	}
+	return Collection{}, false
+}
+
+// CollectionSize returns the number of items in a collection
+func (m *SiteManifest) CollectionSize(collectionID string) int {
+	count := 0
+	for _, item := range m.CollectionItems {
+		if item.CollectionID == collectionID {
+			count++
+		}
+	}
+	return count
+}
+
+// ThemeConfig represents the theme configuration
+type ThemeConfig struct {
+	Name   string                 `json:"name" yaml:"name"`
+	Config map[string]interface{} `json:"config" yaml:"config"`
+}
+
+// MenuItem represents a navigation menu item (pages)
+type MenuItem struct {
+	Type     string     `json:"type" yaml:"type"`
+	Title    string     `json:"title" yaml:"title"`
+	Path     string     `json:"path" yaml:"path"`
+	Slug     string     `json:"slug" yaml:"slug"`
+	NavOrder int        `json:"navOrder" yaml:"navOrder"`
+	Children []MenuItem `json:"children" yaml:"children"`
+}
+
+// CollectionItem represents an individual item in a collection
+type CollectionItem struct {
+	CollectionID string    `json:"collectionId" yaml:"collectionId"`
+	Slug         string    `json:"slug" yaml:"slug"`
+	Path         string    `json:"path" yaml:"path"`
+	Title        string    `json:"title" yaml:"title"`
+	URL          string    `json:"url" yaml:"url"`
+	Date         time.Time `json:"-" yaml:"-"` // Fetched from the item's frontmatter
+	Tags         []string  `json:"-" yaml:"-"` // Fetched from the item's frontmatter
+	Draft        bool      `json:"-" yaml:"-"` // Fetched from the item's frontmatter
+}
+
+// Collection represents a collection definition
+type Collection struct {
+	Name              string `json:"name" yaml:"name"`
+	ContentPath       string `json:"contentPath" yaml:"contentPath"`
+	DefaultItemLayout string `json:"defaultItemLayout" yaml:"defaultItemLayout"`
+	ID                string `json:"id" yaml:"id"`
+}
+
+// LayoutConfig represents layout configuration in frontmatter
+type LayoutConfig struct {
+	CollectionID string `yaml:"collectionId"`
+	Layout       string `yaml:"layout"`
+}
+
+// ContentFile represents a parsed markdown content file
+type ContentFile struct {
+	Title        string                 `json:"title"`
+	Layout       string                 `json:"layout"`
+	Date         time.Time              `json:"date"`
+	Published    bool                   `json:"published"`
+	Draft        bool                   `json:"draft,omitempty"`
+	Description  string                 `json:"description"`
+	Tags         []string               `json:"tags,omitempty"`
+	Author       string                 `json:"author,omitempty"`
+	Categories   []string               `json:"categories,omitempty"`
+	LayoutConfig *LayoutConfig          `json:"layoutConfig,omitempty"`
+	Metadata     map[string]interface{} `json:"-"` // Additional frontmatter
+	Content      string                 `json:"-"` // Markdown content
+}
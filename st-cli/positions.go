@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"st-cli/pkg/sparktype"
+)
+
+// positionStore holds how far through each page the user had scrolled, for
+// every site, keyed by site URL and then content path. Positions are the
+// fraction of the page's lines above the top of the viewport, so they still
+// fit after the page is reflowed to a different width.
+type positionStore struct {
+	path    string
+	sites   map[string]map[string]float64
+	changed bool // Positions have changed since the file was saved
+}
+
+// loadPositions reads the positions file at path. A missing or unreadable
+// file gives no positions, and is replaced when next saved.
+func loadPositions(path string) *positionStore {
+	store := &positionStore{path: path, sites: make(map[string]map[string]float64)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return store
+	}
+	if err := json.Unmarshal(data, &store.sites); err != nil || store.sites == nil {
+		store.sites = make(map[string]map[string]float64)
+	}
+	return store
+}
+
+// get returns the position remembered for a page, or 0 for its top
+func (p *positionStore) get(site, path string) float64 {
+	return p.sites[site][path]
+}
+
+// set remembers the position of a page. Pages read from the top are
+// forgotten, so the file only holds pages left part way through.
+func (p *positionStore) set(site, path string, position float64) {
+	if p.sites[site][path] == position {
+		return
+	}
+	if position <= 0 {
+		delete(p.sites[site], path)
+		if len(p.sites[site]) == 0 {
+			delete(p.sites, site)
+		}
+	} else {
+		if p.sites[site] == nil {
+			p.sites[site] = make(map[string]float64)
+		}
+		p.sites[site][path] = position
+	}
+	p.changed = true
+}
+
+// save writes the positions file if anything has changed
+func (p *positionStore) save() error {
+	if !p.changed {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p.sites, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := sparktype.WriteFileAtomic(p.path, data); err != nil {
+		return err
+	}
+	p.changed = false
+	return nil
+}
+
+// rememberPosition records how far through the page being viewed the user
+// has scrolled
+func (a *App) rememberPosition() {
+	if a.positions == nil || a.state != StateContentView || a.currentPath == "" {
+		return
+	}
+	lines := a.viewport.TotalLineCount()
+	if lines == 0 {
+		return
+	}
+	a.positions.set(a.client.GetBaseURL(), a.currentPath, float64(a.viewport.YOffset)/float64(lines))
+}
+
+// restorePosition scrolls the page just opened to where the user left it
+func (a *App) restorePosition() {
+	if a.positions == nil {
+		return
+	}
+	position := a.positions.get(a.client.GetBaseURL(), a.currentPath)
+	a.viewport.SetYOffset(int(position*float64(a.viewport.TotalLineCount()) + 0.5))
+}
+
+// savePositions writes the remembered scroll positions to disk. Positions
+// are a convenience, so a failure to save them is ignored.
+func (a *App) savePositions() {
+	if a.positions != nil {
+		a.positions.save()
+	}
+}
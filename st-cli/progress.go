@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// progressWidth is the widest a progress bar is drawn
+const progressWidth = 60
+
+// newProgressBar returns a progress bar in the browser's colours, at most
+// width columns wide
+func newProgressBar(width int) progress.Model {
+	if width <= 0 || width > progressWidth {
+		width = progressWidth
+	}
+	return progress.New(progress.WithSolidFill(string(accentColor)), progress.WithWidth(width))
+}
+
+// progressCount describes how much of a batch has been fetched
+func progressCount(done, total int) string {
+	return fmt.Sprintf(tr("%d of %d fetched"), done, total)
+}
+
+// progressLine reports the progress of a batch run by a subcommand. On a
+// terminal it redraws a bar in place; otherwise it prints a line per item, so
+// logs and pipes still show what happened.
+type progressLine struct {
+	out   io.Writer
+	bar   progress.Model
+	total int
+	done  int
+	tty   bool
+}
+
+// newProgressLine starts reporting to out on a batch of total items
+func newProgressLine(out io.Writer, total int) *progressLine {
+	return &progressLine{
+		out:   out,
+		bar:   newProgressBar(progressWidth),
+		total: total,
+		tty:   isTerminal(out),
+	}
+}
+
+// step records that an item has finished, described by label
+func (p *progressLine) step(label string) {
+	p.done++
+	if !p.tty {
+		fmt.Fprintf(p.out, "[%d/%d] %s\n", p.done, p.total, label)
+		return
+	}
+	p.draw()
+}
+
+// fail records that an item has failed, printing a warning about it
+func (p *progressLine) fail(format string, args ...interface{}) {
+	p.done++
+	p.warn(format, args...)
+}
+
+// draw redraws the bar over the current line
+func (p *progressLine) draw() {
+	percent := 1.0
+	if p.total > 0 {
+		percent = float64(p.done) / float64(p.total)
+	}
+	fmt.Fprintf(p.out, "\r\033[K%s %s", p.bar.ViewAs(percent), progressCount(p.done, p.total))
+}
+
+// warn prints a warning to stderr without leaving it tangled in the bar
+func (p *progressLine) warn(format string, args ...interface{}) {
+	if p.tty {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+	fmt.Fprintf(os.Stderr, "warning: "+format+"\n", args...)
+	if p.tty {
+		p.draw()
+	}
+}
+
+// finish ends the bar's line, ready for a summary
+func (p *progressLine) finish() {
+	if p.tty {
+		fmt.Fprintln(p.out)
+	}
+}
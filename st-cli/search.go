@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// snippetRadius is the number of characters shown either side of a match
+const snippetRadius = 40
+
+// searchEntry is a page or collection item in the full-text search index
+type searchEntry struct {
+	Title string
+	Path  string
+	Body  string // Plain text of the content
+}
+
+// SearchResultWrapper wraps a search match for the list component
+type SearchResultWrapper struct {
+	Entry   searchEntry
+	Snippet string
+}
+
+// Title returns the title of the matching content
+func (s SearchResultWrapper) Title() string {
+	return s.Entry.Title
+}
+
+// Description returns the text surrounding the match
+func (s SearchResultWrapper) Description() string {
+	return s.Snippet
+}
+
+// FilterValue returns the value to filter on
+func (s SearchResultWrapper) FilterValue() string {
+	return s.Entry.Title
+}
+
+// SearchIndexedMsg reports that one piece of content has been indexed
+type SearchIndexedMsg struct {
+	index  int
+	entry  *searchEntry // nil if the content couldn't be fetched
+	loadID int
+}
+
+// startSearch opens the search view, building the index first if needed
+func (a *App) startSearch() (tea.Model, tea.Cmd) {
+	if a.manifest == nil {
+		return a, nil
+	}
+
+	if a.searchIndexed {
+		return a.openSearch()
+	}
+
+	a.searchQueue = a.manifest.AllContent()
+	a.searchIndex = nil
+	a.searchProgress = 0
+	a.indexProgress = newProgressBar(a.width)
+	a.beginLoading()
+	a.state = StateIndexing
+	if len(a.searchQueue) == 0 {
+		a.searchIndexed = true
+		return a.openSearch()
+	}
+	return a, a.indexContent(0)
+}
+
+// indexContent fetches the queued content at index and converts it to plain text
+func (a *App) indexContent(index int) tea.Cmd {
+	loadID, ctx := a.loadID, a.loadCtx
+	target := a.searchQueue[index]
+	return func() tea.Msg {
+		content, err := a.client.FetchContent(ctx, target.Path)
+		if err != nil {
+			return SearchIndexedMsg{index: index, loadID: loadID}
+		}
+
+		title := target.Title
+		if content.Title != "" {
+			title = content.Title
+		}
+		return SearchIndexedMsg{
+			index: index,
+			entry: &searchEntry{
+				Title: title,
+				Path:  target.Path,
+				Body:  a.renderer.StripMarkdown(content.Content),
+			},
+			loadID: loadID,
+		}
+	}
+}
+
+// handleSearchIndexed records an indexed entry and moves on to the next one
+func (a *App) handleSearchIndexed(msg SearchIndexedMsg) (tea.Model, tea.Cmd) {
+	if msg.loadID != a.loadID {
+		return a, nil
+	}
+
+	a.searchProgress = msg.index + 1
+
+	if msg.entry != nil {
+		a.searchIndex = append(a.searchIndex, *msg.entry)
+	}
+
+	next := msg.index + 1
+	if next < len(a.searchQueue) {
+		return a, a.indexContent(next)
+	}
+
+	// The index is kept for the rest of the session
+	a.searchIndexed = true
+	return a.openSearch()
+}
+
+// openSearch shows the search input and results
+func (a *App) openSearch() (tea.Model, tea.Cmd) {
+	if a.searchInput.Prompt == "" {
+		a.searchInput = textinput.New()
+		a.searchInput.Prompt = tr("Search: ")
+		a.searchInput.Placeholder = tr("words to find")
+	}
+	a.searchInput.Focus()
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.searchList = list.New(nil, delegate, a.width, a.height-4)
+	a.searchList.SetShowTitle(false)
+	a.searchList.SetShowStatusBar(false)
+	a.searchList.SetShowHelp(false)
+	a.searchList.SetFilteringEnabled(false)
+
+	a.state = StateSearch
+	a.runSearch()
+	return a, textinput.Blink
+}
+
+// runSearch refreshes the results for the current query
+func (a *App) runSearch() {
+	query := strings.TrimSpace(a.searchInput.Value())
+	if query == "" {
+		a.searchList.SetItems(nil)
+		return
+	}
+
+	var items []list.Item
+	for _, entry := range a.searchIndex {
+		if snippet, ok := matchSnippet(entry, query); ok {
+			items = append(items, SearchResultWrapper{Entry: entry, Snippet: snippet})
+		}
+	}
+	a.searchList.SetItems(items)
+	a.searchList.ResetSelected()
+}
+
+// matchSnippet reports whether the entry matches the query, case-insensitively,
+// returning the text around the first match in the body
+func matchSnippet(entry searchEntry, query string) (string, bool) {
+	lowerQuery := strings.ToLower(query)
+	body := strings.Join(strings.Fields(entry.Body), " ")
+
+	// Lowercasing can change byte lengths for some scripts, so match on
+	// runes to keep offsets into the original body valid
+	bodyRunes := []rune(body)
+	lowerBody := []rune(strings.ToLower(body))
+	queryRunes := []rune(lowerQuery)
+
+	if pos := runeIndex(lowerBody, queryRunes); pos >= 0 {
+		start := pos - snippetRadius
+		if start < 0 {
+			start = 0
+		}
+		end := pos + len(queryRunes) + snippetRadius
+		if end > len(bodyRunes) {
+			end = len(bodyRunes)
+		}
+
+		snippet := string(bodyRunes[start:end])
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(bodyRunes) {
+			snippet += "…"
+		}
+		return snippet, true
+	}
+
+	if strings.Contains(strings.ToLower(entry.Title), lowerQuery) {
+		return tr("Title match"), true
+	}
+
+	return "", false
+}
+
+// runeIndex returns the index of the first occurrence of sub in s, or -1
+func runeIndex(s, sub []rune) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// handleSearchKey handles keyboard input while the search view is open
+func (a *App) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return a.quit()
+
+	case tea.KeyEsc:
+		a.searchInput.Blur()
+		a.state = StateMainMenu
+		a.setupUI()
+		return a, nil
+
+	case tea.KeyEnter:
+		// The input keeps its focus until the page has loaded, so that
+		// cancelling the load returns to a search that can still be typed in
+		if result, ok := a.searchList.SelectedItem().(SearchResultWrapper); ok {
+			a.beginLoading()
+			return a, a.loadContent(result.Entry.Path)
+		}
+		return a, nil
+
+	case tea.KeyUp, tea.KeyDown:
+		var cmd tea.Cmd
+		a.searchList, cmd = a.searchList.Update(msg)
+		return a, cmd
+	}
+
+	var cmd tea.Cmd
+	a.searchInput, cmd = a.searchInput.Update(msg)
+	a.runSearch()
+	return a, cmd
+}
+
+// searchView renders the search input and results
+func (a *App) searchView() string {
+	help := helpStyle.Render(tr("type to search • ↑/↓: navigate • enter: open • esc: back"))
+	status := statusStyle.Render(fmt.Sprintf(tr("%d results in %d pages"), len(a.searchList.Items()), len(a.searchIndex)))
+	return fmt.Sprintf("%s\n%s\n%s\n%s", a.searchInput.View(), status, a.searchList.View(), help)
+}
+
+// indexingView renders the indexing progress
+func (a *App) indexingView() string {
+	percent := 1.0
+	if len(a.searchQueue) > 0 {
+		percent = float64(a.searchProgress) / float64(len(a.searchQueue))
+	}
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s", tr("Indexing site for search"),
+		a.indexProgress.ViewAs(percent), progressCount(a.searchProgress, len(a.searchQueue)), helpStyle.Render(tr("esc: cancel")))
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"st-cli/pkg/sparktype"
+)
+
+// snapshotWriter adds files to a snapshot, skipping any already added
+type snapshotWriter struct {
+	zip   *zip.Writer
+	added map[string]bool
+	size  int64 // Total uncompressed size of the files added
+}
+
+// add stores data in the snapshot under name, a path relative to the site root
+func (s *snapshotWriter) add(name string, data []byte) error {
+	if s.added[name] {
+		return nil
+	}
+	w, err := s.zip.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	s.added[name] = true
+	s.size += int64(len(data))
+	return nil
+}
+
+// formatSize describes a number of bytes for people
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", bytes)
+}
+
+// runSnapshot implements `st-cli snapshot`, saving the manifest, every page
+// and collection item and the images they use to a zip file that the
+// browser can open without a network connection, reporting progress to out
+func runSnapshot(args []string, out io.Writer) int {
+	config := DefaultConfig()
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: st-cli snapshot [flags] <site-url> <file.zip>")
+		fs.PrintDefaults()
+	}
+	config.RegisterClientFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	siteURL, zipPath := fs.Arg(0), fs.Arg(1)
+
+	client, err := config.NewClient(siteURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, manifestPath, manifestBody, err := client.FetchManifestSource(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	// Write to a temporary file so a failed snapshot doesn't replace a good one
+	file, err := os.CreateTemp(filepath.Dir(zipPath), ".st-cli-snapshot-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	defer os.Remove(file.Name())
+
+	snapshot := &snapshotWriter{zip: zip.NewWriter(file), added: map[string]bool{}}
+	// The manifest goes where a snapshot is read from, wherever the site
+	// keeps it
+	manifestName := "_site/manifest.json"
+	if ext := path.Ext(manifestPath); ext == ".yaml" || ext == ".yml" {
+		manifestName = "_site/manifest.yaml"
+	}
+	if err := snapshot.add(manifestName, manifestBody); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	refs := manifest.AllContent()
+	saved := 0
+	progress := newProgressLine(out, len(refs))
+	for _, ref := range refs {
+		// Like images, content hosted elsewhere is left out
+		if sparktype.IsAbsoluteURL(ref.Path) {
+			progress.fail("skipping %s: not on the site", ref.Path)
+			continue
+		}
+		body, err := client.FetchContentSource(context.Background(), ref.Path)
+		if err != nil {
+			progress.fail("skipping %s: %v", ref.Path, err)
+			continue
+		}
+		// Content is saved in the standard layout, whatever its prefix on the
+		// site, so the snapshot opens without --content-prefix
+		name := strings.TrimPrefix(sparktype.ContentLocation(sparktype.DefaultContentPrefix, ref.Path), "/")
+		if err := snapshot.add(name, body); err != nil {
+			file.Close()
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 1
+		}
+		saved++
+
+		// Images are saved when they are on the site; those hosted elsewhere
+		// are left as links
+		if content, err := client.ParseMarkdown(string(body)); err == nil {
+			for _, imageURL := range client.ContentImages(content) {
+				imageName, ok := sparktype.SnapshotName(client.GetBaseURL(), imageURL)
+				if !ok || snapshot.added[imageName] {
+					continue
+				}
+				data, err := client.Get(context.Background(), imageURL)
+				if err != nil {
+					progress.warn("skipping image %s: %v", imageName, err)
+					continue
+				}
+				if err := snapshot.add(imageName, data); err != nil {
+					file.Close()
+					fmt.Fprintf(os.Stderr, "error: %v\n", err)
+					return 1
+				}
+			}
+		}
+
+		progress.step(fmt.Sprintf("%s (%s so far)", ref.Path, formatSize(snapshot.size)))
+	}
+	progress.finish()
+
+	if err := snapshot.zip.Close(); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := os.Rename(file.Name(), zipPath); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	info, _ := os.Stat(zipPath)
+	var zipped int64
+	if info != nil {
+		zipped = info.Size()
+	}
+	fmt.Fprintf(out, "Saved %d of %d pages and %d files in all (%s, %s compressed) to %s\n",
+		saved, len(refs), len(snapshot.added), formatSize(snapshot.size), formatSize(zipped), zipPath)
+	if saved < len(refs) {
+		return 1
+	}
+	return 0
+}
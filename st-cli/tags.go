@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// TagItem is an entry in the tag filter picker
+type TagItem struct {
+	Tag   string // Empty for the entry that clears the filter
+	Count int
+}
+
+// Title returns the tag name
+func (t TagItem) Title() string {
+	if t.Tag == "" {
+		return tr("All items")
+	}
+	return "#" + t.Tag
+}
+
+// Description returns how many items carry the tag
+func (t TagItem) Description() string {
+	if t.Count == 1 {
+		return tr("1 item")
+	}
+	return fmt.Sprintf(tr("%d items"), t.Count)
+}
+
+// FilterValue returns the value to filter on
+func (t TagItem) FilterValue() string {
+	return t.Tag
+}
+
+// collectionTags returns the distinct tags across the collection with the
+// number of items carrying each, sorted by name
+func (a *App) collectionTags() []TagItem {
+	counts := make(map[string]int)
+	for _, item := range a.collectionAll {
+		for _, tag := range item.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]TagItem, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagItem{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
+
+// showTagFilter opens the tag picker for the current collection
+func (a *App) showTagFilter() (tea.Model, tea.Cmd) {
+	tags := a.collectionTags()
+
+	items := []list.Item{TagItem{Count: len(a.collectionAll)}}
+	selected := 0
+	for i, tag := range tags {
+		items = append(items, tag)
+		if tag.Tag == a.tagFilter {
+			selected = i + 1
+		}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.tagList = list.New(items, delegate, a.width, a.height-4)
+	a.tagList.Title = fmt.Sprintf(tr("Filter %s by tag"), a.collectionTitle)
+	a.tagList.SetShowStatusBar(false)
+	a.tagList.SetShowHelp(false)
+	a.tagList.Select(selected)
+
+	a.state = StateTagFilter
+	return a, nil
+}
+
+// selectTagFilter applies the tag chosen in the picker and returns to the listing
+func (a *App) selectTagFilter() (tea.Model, tea.Cmd) {
+	if tag, ok := a.tagList.SelectedItem().(TagItem); ok {
+		a.tagFilter = tag.Tag
+		a.applyCollectionFilter()
+	}
+
+	a.state = StateCollectionListing
+	a.setupCollectionListingUI()
+	return a, nil
+}
+
+// applyCollectionFilter narrows the listing to items carrying the active tag
+// and returns to the first page
+func (a *App) applyCollectionFilter() {
+	if a.tagFilter == "" {
+		a.collectionItems = a.collectionAll
+	} else {
+		var items []sparktype.CollectionItem
+		for _, item := range a.collectionAll {
+			for _, tag := range item.Tags {
+				if tag == a.tagFilter {
+					items = append(items, item)
+					break
+				}
+			}
+		}
+		a.collectionItems = items
+	}
+
+	a.paginate()
+}
@@ -1,41 +1,56 @@
+//go:build ignore
+
+// Command test checks the client and renderer against a running site. Run
+// it with `go run test.go`.
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
+
+	"st-cli/pkg/sparktype"
 )
 
 func main() {
-	siteURL := "http://localhost:8080"
+	if err := checkSite(os.Stdout, "http://localhost:8080"); err != nil {
+		log.Fatal(err)
+	}
+}
 
+// checkSite exercises the client and renderer against a running site,
+// reporting each step to out
+func checkSite(out io.Writer, siteURL string) error {
 	// Test client creation and manifest fetching
-	client, err := NewClient(siteURL)
+	client, err := sparktype.NewClient(siteURL)
 	if err != nil {
-		log.Fatal("Failed to create client:", err)
+		return fmt.Errorf("failed to create client: %v", err)
 	}
 
-	fmt.Println("✓ Client created successfully")
-	fmt.Printf("  Base URL: %s\n", client.GetBaseURL())
+	fmt.Fprintln(out, "✓ Client created successfully")
+	fmt.Fprintf(out, "  Base URL: %s\n", client.GetBaseURL())
 
 	// Test manifest fetching
-	manifest, err := client.FetchManifest()
+	manifest, err := client.FetchManifest(context.Background())
 	if err != nil {
-		log.Fatal("Failed to fetch manifest:", err)
+		return fmt.Errorf("failed to fetch manifest: %v", err)
 	}
 
-	fmt.Println("\n✓ Manifest fetched successfully")
-	fmt.Printf("  Site Title: %s\n", manifest.Title)
-	fmt.Printf("  Site ID: %s\n", manifest.SiteID)
-	fmt.Printf("  Generator: %s\n", manifest.GeneratorVersion)
+	fmt.Fprintln(out, "\n✓ Manifest fetched successfully")
+	fmt.Fprintf(out, "  Site Title: %s\n", manifest.Title)
+	fmt.Fprintf(out, "  Site ID: %s\n", manifest.SiteID)
+	fmt.Fprintf(out, "  Generator: %s\n", manifest.GeneratorVersion)
 
 	// Show structure
-	fmt.Printf("\n📄 Pages (%d):\n", len(manifest.Structure))
+	fmt.Fprintf(out, "\n📄 Pages (%d):\n", len(manifest.Structure))
 	for _, item := range manifest.Structure {
-		fmt.Printf("  - %s (%s)\n", item.Title, item.Path)
+		fmt.Fprintf(out, "  - %s (%s)\n", item.Title, item.Path)
 	}
 
 	// Show collections
-	fmt.Printf("\n📁 Collections (%d):\n", len(manifest.Collections))
+	fmt.Fprintf(out, "\n📁 Collections (%d):\n", len(manifest.Collections))
 	collectionCounts := make(map[string]int)
 	for _, item := range manifest.CollectionItems {
 		collectionCounts[item.CollectionID]++
@@ -43,13 +58,13 @@ func main() {
 
 	for _, collection := range manifest.Collections {
 		count := collectionCounts[collection.ID]
-		fmt.Printf("  - %s (%d items)\n", collection.Name, count)
+		fmt.Fprintf(out, "  - %s (%d items)\n", collection.Name, count)
 
 		// Show first few items
 		itemCount := 0
 		for _, item := range manifest.CollectionItems {
 			if item.CollectionID == collection.ID && itemCount < 3 {
-				fmt.Printf("    • %s\n", item.Title)
+				fmt.Fprintf(out, "    • %s\n", item.Title)
 				itemCount++
 			}
 		}
@@ -57,37 +72,38 @@ func main() {
 
 	// Test content fetching
 	if len(manifest.Structure) > 0 {
-		fmt.Printf("\n🔄 Testing content fetch for: %s\n", manifest.Structure[0].Title)
-		content, err := client.FetchContent(manifest.Structure[0].Path)
+		fmt.Fprintf(out, "\n🔄 Testing content fetch for: %s\n", manifest.Structure[0].Title)
+		content, err := client.FetchContent(context.Background(), manifest.Structure[0].Path)
 		if err != nil {
-			fmt.Printf("  ❌ Error: %v\n", err)
+			fmt.Fprintf(out, "  ❌ Error: %v\n", err)
 		} else {
-			fmt.Printf("  ✓ Content fetched successfully\n")
-			fmt.Printf("    Title: %s\n", content.Title)
-			fmt.Printf("    Layout: %s\n", content.Layout)
-			fmt.Printf("    Content Length: %d chars\n", len(content.Content))
+			fmt.Fprintf(out, "  ✓ Content fetched successfully\n")
+			fmt.Fprintf(out, "    Title: %s\n", content.Title)
+			fmt.Fprintf(out, "    Layout: %s\n", content.Layout)
+			fmt.Fprintf(out, "    Content Length: %d chars\n", len(content.Content))
 		}
 	}
 
 	// Test content renderer
-	fmt.Printf("\n🎨 Testing content renderer\n")
-	renderer, err := NewContentRenderer()
+	fmt.Fprintf(out, "\n🎨 Testing content renderer\n")
+	renderer, err := sparktype.NewContentRenderer()
 	if err != nil {
-		fmt.Printf("  ❌ Error creating renderer: %v\n", err)
+		fmt.Fprintf(out, "  ❌ Error creating renderer: %v\n", err)
 	} else {
-		fmt.Printf("  ✓ Content renderer created successfully\n")
+		fmt.Fprintf(out, "  ✓ Content renderer created successfully\n")
 
 		// Test markdown rendering
 		testMarkdown := "# Test\n\nThis is **bold** and *italic* text.\n\n- Item 1\n- Item 2"
 		rendered, err := renderer.RenderMarkdown(testMarkdown)
 		if err != nil {
-			fmt.Printf("  ❌ Render error: %v\n", err)
+			fmt.Fprintf(out, "  ❌ Render error: %v\n", err)
 		} else {
-			fmt.Printf("  ✓ Markdown rendered successfully (%d chars)\n", len(rendered))
+			fmt.Fprintf(out, "  ✓ Markdown rendered successfully (%d chars)\n", len(rendered))
 		}
 	}
 
-	fmt.Println("\n🎉 All tests passed! The CLI components are working correctly.")
-	fmt.Println("\nTo use the interactive CLI in a proper terminal:")
-	fmt.Println("  ./st-cli http://localhost:8080")
-}
\ No newline at end of file
+	fmt.Fprintln(out, "\n🎉 All tests passed! The CLI components are working correctly.")
+	fmt.Fprintln(out, "\nTo use the interactive CLI in a proper terminal:")
+	fmt.Fprintln(out, "  ./st-cli http://localhost:8080")
+	return nil
+}
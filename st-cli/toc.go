@@ -0,0 +1,117 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"st-cli/pkg/sparktype"
+)
+
+// ansiSequence matches the escape sequences glamour uses for styling
+var ansiSequence = regexp.MustCompile("\x1b\\[[0-9;]*[a-zA-Z]")
+
+// TOCItem is a heading in the table of contents overlay
+type TOCItem struct {
+	sparktype.Heading
+	Indent int // Nesting depth relative to the shallowest heading
+}
+
+// Title returns the heading text, indented by its depth
+func (t TOCItem) Title() string {
+	return strings.Repeat("  ", t.Indent) + t.Text
+}
+
+// Description returns nothing; the overlay shows one line per heading
+func (t TOCItem) Description() string {
+	return ""
+}
+
+// FilterValue returns the value to filter on
+func (t TOCItem) FilterValue() string {
+	return t.Text
+}
+
+// showTOC opens the table of contents for the current page
+func (a *App) showTOC() (tea.Model, tea.Cmd) {
+	if a.content == nil {
+		return a, nil
+	}
+
+	headings := a.renderer.ExtractHeadings(a.content.Content)
+	if len(headings) == 0 {
+		return a, a.setStatus(tr("This page has no headings"))
+	}
+
+	minLevel := headings[0].Level
+	for _, h := range headings {
+		if h.Level < minLevel {
+			minLevel = h.Level
+		}
+	}
+
+	items := make([]list.Item, len(headings))
+	for i, h := range headings {
+		items[i] = TOCItem{Heading: h, Indent: h.Level - minLevel}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	a.tocList = list.New(items, delegate, a.width, a.height-4)
+	a.tocList.Title = tr("Contents")
+	a.tocList.SetShowStatusBar(false)
+	a.tocList.SetShowHelp(false)
+
+	a.state = StateTOC
+	return a, nil
+}
+
+// selectTOCHeading scrolls the content to the chosen heading
+func (a *App) selectTOCHeading() (tea.Model, tea.Cmd) {
+	a.state = StateContentView
+
+	item, ok := a.tocList.SelectedItem().(TOCItem)
+	if !ok {
+		return a, nil
+	}
+
+	// Headings with the same text are told apart by their order on the page
+	occurrence := 0
+	for _, listItem := range a.tocList.Items()[:a.tocList.Index()] {
+		if other, ok := listItem.(TOCItem); ok && other.Text == item.Text {
+			occurrence++
+		}
+	}
+
+	line := headingLine(a.contentLines, item.Text, occurrence)
+	if line < 0 {
+		return a, a.setStatus(tr("Couldn't find that heading on the page"))
+	}
+	a.viewport.SetYOffset(line)
+	return a, nil
+}
+
+// headingLine returns the index of the line in the displayed content that
+// holds the given occurrence of a heading, or -1
+func headingLine(lines []string, text string, occurrence int) int {
+	for i, line := range lines {
+		plain := strings.TrimSpace(ansiSequence.ReplaceAllString(line, ""))
+		// Glamour keeps the "#" markers on most heading levels
+		if strings.TrimSpace(strings.TrimLeft(plain, "#")) != text {
+			continue
+		}
+		if occurrence == 0 {
+			return i
+		}
+		occurrence--
+	}
+	return -1
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"st-cli/pkg/sparktype"
+)
+
+// collectionNodePrefix marks tree nodes that stand for a whole collection
+const collectionNodePrefix = "collection:"
+
+// treeItems returns the site structure as a tree, with pages and
+// collections expanded according to treeExpanded
+func (a *App) treeItems() []NavigationItem {
+	var items []NavigationItem
+
+	var addPages func(pages []sparktype.MenuItem, level int, parentPath string)
+	addPages = func(pages []sparktype.MenuItem, level int, parentPath string) {
+		for _, page := range pages {
+			items = append(items, NavigationItem{
+				Title:       page.Title,
+				Description: a.pageDescription(page.Path),
+				Type:        "page",
+				Path:        page.Path,
+				Level:       level,
+				ParentPath:  parentPath,
+				Children:    page.Children,
+			})
+			if a.treeExpanded[page.Path] {
+				addPages(page.Children, level+1, page.Path)
+			}
+		}
+	}
+	addPages(a.manifest.Structure, 0, "")
+
+	for _, collection := range a.manifest.Collections {
+		nodePath := collectionNodePrefix + collection.ID
+		items = append(items, NavigationItem{
+			Title:        collection.Name,
+			Description:  itemCount(a.manifest.CollectionSize(collection.ID)),
+			Type:         "collection",
+			Path:         nodePath,
+			CollectionID: collection.ID,
+		})
+		if !a.treeExpanded[nodePath] {
+			continue
+		}
+		for _, item := range a.manifest.CollectionItems {
+			if item.CollectionID == collection.ID {
+				items = append(items, NavigationItem{
+					Title:        item.Title,
+					Type:         "item",
+					Path:         item.Path,
+					Level:        1,
+					ParentPath:   nodePath,
+					CollectionID: item.CollectionID,
+				})
+			}
+		}
+	}
+
+	return items
+}
+
+// expandable reports whether a tree node has anything beneath it
+func expandable(item NavigationItem) bool {
+	return len(item.Children) > 0 || item.Type == "collection"
+}
+
+// toggleTreeMode switches the main menu between the flat menu and the tree
+func (a *App) toggleTreeMode() (tea.Model, tea.Cmd) {
+	a.treeMode = !a.treeMode
+	a.buildNavigationItems()
+	a.setupUI()
+	return a, nil
+}
+
+// refreshTree rebuilds the tree after a node is expanded or collapsed,
+// keeping the node at path selected
+func (a *App) refreshTree(path string) {
+	a.navigationItems = a.treeItems()
+	a.setupUI()
+	for i, item := range a.navigationItems {
+		if item.Path == path {
+			a.list.Select(i)
+			break
+		}
+	}
+}
+
+// expandTreeNode expands the selected node
+func (a *App) expandTreeNode() (tea.Model, tea.Cmd) {
+	wrapper, ok := a.list.SelectedItem().(NavigationItemWrapper)
+	if !ok || !expandable(wrapper.NavigationItem) {
+		return a, nil
+	}
+
+	if a.treeExpanded == nil {
+		a.treeExpanded = make(map[string]bool)
+	}
+	a.treeExpanded[wrapper.Path] = true
+	a.refreshTree(wrapper.Path)
+	return a, nil
+}
+
+// collapseTreeNode collapses the selected node, or moves up to its parent if
+// it is already collapsed
+func (a *App) collapseTreeNode() (tea.Model, tea.Cmd) {
+	wrapper, ok := a.list.SelectedItem().(NavigationItemWrapper)
+	if !ok {
+		return a, nil
+	}
+
+	if a.treeExpanded[wrapper.Path] {
+		delete(a.treeExpanded, wrapper.Path)
+		a.refreshTree(wrapper.Path)
+		return a, nil
+	}
+
+	if wrapper.ParentPath != "" {
+		a.refreshTree(wrapper.ParentPath)
+	}
+	return a, nil
+}
+
+// openCollection shows the listing for a collection picked from the tree
+func (a *App) openCollection(item NavigationItem) (tea.Model, tea.Cmd) {
+	a.visitPage(item)
+	a.showCollectionListing(item.CollectionID, item.Title)
+	a.collectionPath = ""
+	a.state = StateCollectionListing
+	a.setupCollectionListingUI()
+	return a, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The generator versions whose manifests st-cli is known to read correctly:
+// MinGeneratorVersion and every later release before MaxGeneratorVersion
+const (
+	MinGeneratorVersion = "1.0.0"
+	MaxGeneratorVersion = "2.0.0"
+)
+
+// warningStyle styles the banner warning about a site's generator version
+var warningStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFA500"))
+
+// parseVersion reads a version such as "1.4.2" or "v1.4" into its major,
+// minor and patch numbers, ignoring any prerelease or build suffix. Missing
+// numbers are 0.
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) > len(parts) {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, the same as or
+// newer than b
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// generatorWarning explains how a site's generator version falls outside the
+// supported range, or returns "" if it doesn't. Sites that don't record a
+// version aren't warned about.
+func generatorWarning(version string) string {
+	if version == "" {
+		return ""
+	}
+	v, ok := parseVersion(version)
+	if !ok {
+		return fmt.Sprintf(tr("The site's generator version %q isn't one st-cli recognises; some pages may not display as intended"), version)
+	}
+
+	supported := fmt.Sprintf(tr("%s and later, before %s"), MinGeneratorVersion, MaxGeneratorVersion)
+	min, _ := parseVersion(MinGeneratorVersion)
+	max, _ := parseVersion(MaxGeneratorVersion)
+	switch {
+	case compareVersions(v, min) < 0:
+		return fmt.Sprintf(tr("The site was built by generator %s, older than st-cli supports (%s); some pages may not display as intended"), version, supported)
+	case compareVersions(v, max) >= 0:
+		return fmt.Sprintf(tr("The site was built by generator %s, newer than st-cli supports (%s); some pages may not display as intended"), version, supported)
+	}
+	return ""
+}
+
+// checkGeneratorVersion sets the warning about the manifest's generator
+// version. A warning the user has dismissed stays hidden until it changes.
+func (a *App) checkGeneratorVersion() {
+	warning := generatorWarning(a.manifest.GeneratorVersion)
+	if warning != a.versionWarning {
+		a.versionWarning = warning
+		a.warningDismissed = false
+	}
+}
+
+// warningBanner renders the generator version warning, or "" when there is
+// none to show. The warning is shortened to fit the window, keeping the key
+// that dismisses it in view.
+func (a *App) warningBanner() string {
+	if a.versionWarning == "" || a.warningDismissed {
+		return ""
+	}
+	text := "⚠ " + a.versionWarning
+	hint := " • " + tr("x: dismiss")
+	if room := a.width - lipgloss.Width(hint); a.width > 0 && lipgloss.Width(text) > room && room > 1 {
+		text = lipgloss.NewStyle().MaxWidth(room-1).Render(text) + "…"
+	}
+	return warningStyle.Render(text + hint)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the site directory must be quiet after a change
+// before it is reloaded, so that saving several files reloads once
+const watchDebounce = 200 * time.Millisecond
+
+// SiteChangedMsg reports that files in a local site have changed
+type SiteChangedMsg struct{}
+
+// siteWatcher watches a local site directory and everything beneath it
+type siteWatcher struct {
+	watcher *fsnotify.Watcher
+}
+
+// watchSite starts watching the directory tree at root
+func watchSite(root string) (*siteWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &siteWatcher{watcher: watcher}
+	if err := w.addTree(root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// addTree watches dir and every directory beneath it, since fsnotify only
+// reports changes to the immediate contents of a watched directory
+func (w *siteWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return w.watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// wait returns a command that blocks until the site changes, then reports
+// it once things have been quiet for watchDebounce
+func (w *siteWatcher) wait() tea.Cmd {
+	return func() tea.Msg {
+		var quiet <-chan time.Time
+		for {
+			select {
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return nil
+				}
+				if event.Has(fsnotify.Create) {
+					// New directories need watching too; if this fails the
+					// directory's later changes are missed, but not this one
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						w.addTree(event.Name)
+					}
+				}
+				quiet = time.After(watchDebounce)
+
+			case _, ok := <-w.watcher.Errors:
+				// An overflow or similar only means some events were lost,
+				// and the next change reloads everything anyway
+				if !ok {
+					return nil
+				}
+
+			case <-quiet:
+				return SiteChangedMsg{}
+			}
+		}
+	}
+}
+
+// startWatching watches the site directory when the site is read from disk,
+// returning the command that waits for the first change
+func (a *App) startWatching() tea.Cmd {
+	root := a.client.LocalRoot()
+	if root == "" {
+		return nil
+	}
+	if site := filepath.Join(root, "_site"); isDir(site) {
+		root = site
+	}
+
+	watcher, err := watchSite(root)
+	if err != nil {
+		return a.setStatus(fmt.Sprintf(tr("Not watching for changes: %v"), err))
+	}
+	a.watcher = watcher
+	return watcher.wait()
+}
+
+// isDir reports whether path is an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}